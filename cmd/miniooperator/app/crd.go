@@ -0,0 +1,135 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"sigs.k8s.io/yaml"
+
+	"github.com/3Xpl0it3r/minio-operator/cmd/miniooperator/options"
+	"github.com/3Xpl0it3r/minio-operator/pkg/crd"
+)
+
+// NewCRDCommand return the command which prints, installs or uninstalls the crd of the operator
+func NewCRDCommand() *cobra.Command {
+	opts := options.NewCRDOptions()
+	cmd := &cobra.Command{
+		Use:   "crd",
+		Short: "Print, install or uninstall the CustomResourceDefinitions",
+		Long:  "Print, install or uninstall the CustomResourceDefinitions of the operator, run the operator with --install-crds=false if they are installed by this command or a pipeline",
+	}
+	nfs := opts.NamedFlagSets()
+	for _, f := range nfs.FlagSets {
+		cmd.PersistentFlags().AddFlagSet(f)
+	}
+	setUsageAndHelp(cmd, nfs)
+
+	cmd.AddCommand(
+		newCRDSubCommand(opts, "print", "Print the CustomResourceDefinitions as yaml", func(conversion *extensionapiv1.WebhookClientConfig, out io.Writer) error {
+			return printCRDs(crd.CustomResourceDefinitions(conversion), out)
+		}),
+		newCRDSubCommand(opts, "install", "Create or update the CustomResourceDefinitions and wait until they are established", func(conversion *extensionapiv1.WebhookClientConfig, out io.Writer) error {
+			extClientSet, err := buildExtClientSet(opts)
+			if err != nil {
+				return err
+			}
+			if err = crd.InstallCustomResourceDefineToApiServer(extClientSet, conversion); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%d CustomResourceDefinitions are installed\n", len(crd.CustomResourceDefinitions(conversion)))
+			return nil
+		}),
+		newCRDSubCommand(opts, "uninstall", "Delete the CustomResourceDefinitions, all custom resources are deleted with them", func(conversion *extensionapiv1.WebhookClientConfig, out io.Writer) error {
+			extClientSet, err := buildExtClientSet(opts)
+			if err != nil {
+				return err
+			}
+			if err = crd.UnInstallCustomResourceDefineToApiServer(extClientSet); err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%d CustomResourceDefinitions are uninstalled\n", len(crd.CustomResourceDefinitions(nil)))
+			return nil
+		}),
+	)
+	return cmd
+}
+
+// newCRDSubCommand return a subcommand of crd, run is called with the conversion webhook built from opts, it is nil
+// if --conversion-webhook is not set
+func newCRDSubCommand(opts *options.CRDOptions, use, short string, run func(*extensionapiv1.WebhookClientConfig, io.Writer) error) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  short,
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); len(err) != 0 {
+				return fmt.Errorf("Options validate failed, %v. ", err)
+			}
+			if err := opts.Complete(); err != nil {
+				return fmt.Errorf("Options Complete failed %v. ", err)
+			}
+			var conversion *extensionapiv1.WebhookClientConfig
+			if opts.ConversionWebhook {
+				caBundle, err := os.ReadFile(opts.WebhookCAFile)
+				if err != nil {
+					return fmt.Errorf("read webhook ca failed: %v", err)
+				}
+				conversion = newWebhookClientConfig(opts.WebhookServiceNamespace, opts.WebhookServiceName, opts.WebhookServicePort, caBundle)
+			}
+			return run(conversion, cmd.OutOrStdout())
+		},
+	}
+}
+
+func buildExtClientSet(opts *options.CRDOptions) (extensionsclientset.Interface, error) {
+	restConfig, err := buildKubeConfig("", opts.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("build kubeConfig failed: %v", err)
+	}
+	return extensionsclientset.NewForConfig(restConfig)
+}
+
+// printCRDs print crd as a yaml stream which can be applied by kubectl
+func printCRDs(crds []*extensionapiv1.CustomResourceDefinition, out io.Writer) error {
+	for _, crObj := range crds {
+		crObj.SetGroupVersionKind(extensionapiv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"))
+		data, err := yaml.Marshal(crObj)
+		if err != nil {
+			return err
+		}
+		// drop status and the empty creationTimestamp, they are set by apiserver
+		object := map[string]interface{}{}
+		if err = yaml.Unmarshal(data, &object); err != nil {
+			return err
+		}
+		delete(object, "status")
+		if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "creationTimestamp")
+		}
+		if data, err = yaml.Marshal(object); err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
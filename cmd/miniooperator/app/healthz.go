@@ -0,0 +1,88 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/3Xpl0it3r/minio-operator/pkg/controller"
+)
+
+// healthChecker serve liveness and readiness probes of the operator process
+type healthChecker struct {
+	mu             sync.RWMutex
+	crdEstablished bool
+	controllers    []controller.Controller
+	stallTimeout   time.Duration
+}
+
+func newHealthChecker(stallTimeout time.Duration) *healthChecker {
+	return &healthChecker{stallTimeout: stallTimeout}
+}
+
+func (h *healthChecker) setCRDEstablished() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.crdEstablished = true
+}
+
+// setControllers set the controllers which are running, nil means no controller is running(standby replica)
+func (h *healthChecker) setControllers(controllers []controller.Controller) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.controllers = controllers
+}
+
+// ready return nil if crd is established and the running controllers(if any) have synced
+// standby replicas are ready as soon as crd is established
+func (h *healthChecker) ready() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if !h.crdEstablished {
+		return errors.New("crd is not established")
+	}
+	for _, c := range h.controllers {
+		if err := c.Ready(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// live return error if one of the running controllers is wedged
+func (h *healthChecker) live() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, c := range h.controllers {
+		if err := c.Live(h.stallTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func probeHandler(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := check(); err != nil {
+			http.Error(w, fmt.Sprintf("unhealthy: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "ok")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/klog/v2"
+
+	"github.com/3Xpl0it3r/minio-operator/cmd/miniooperator/options"
+)
+
+// runWithLeaderElection campaigns for the lease and calls run only when this instance is the leader.
+// the stopCh given to run is closed once the lease is lost or signalCh is closed, if the lease is lost
+// an error is returned so the process exits and comes back as a candidate
+func runWithLeaderElection(o *options.LeaderElectionOptions, kubeClientSet kubernetes.Interface, signalCh <-chan struct{}, run func(stopCh <-chan struct{}) error) error {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return fmt.Errorf("get hostname failed: %v", err)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock, err := resourcelock.New(resourcelock.LeasesResourceLock, o.LeaseNamespace, o.LeaseName,
+		kubeClientSet.CoreV1(), kubeClientSet.CoordinationV1(), resourcelock.ResourceLockConfig{Identity: identity})
+	if err != nil {
+		return fmt.Errorf("create leader election lock failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-signalCh:
+			klog.Infof("received signal, give up leader election")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	leading := make(chan context.Context, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   o.LeaseDuration,
+		RenewDeadline:   o.RenewDeadline,
+		RetryPeriod:     o.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            o.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				klog.Infof("%s acquired lease %s/%s", identity, o.LeaseNamespace, o.LeaseName)
+				leading <- leaderCtx
+			},
+			OnStoppedLeading: func() {
+				klog.Infof("%s stopped leading", identity)
+			},
+			OnNewLeader: func(current string) {
+				if current != identity {
+					klog.Infof("current leader is %s", current)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create leader elector failed: %v", err)
+	}
+
+	electionDone := make(chan struct{})
+	go func() {
+		defer close(electionDone)
+		elector.Run(ctx)
+	}()
+
+	select {
+	case leaderCtx := <-leading:
+		runErr := run(leaderCtx.Done())
+		lost := ctx.Err() == nil
+		cancel()
+		<-electionDone
+		if runErr != nil {
+			return runErr
+		}
+		if lost {
+			return fmt.Errorf("leader election lost")
+		}
+		return nil
+	case <-electionDone:
+		// context is canceled before we became leader
+		return nil
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/3Xpl0it3r/minio-operator/cmd/miniooperator/options"
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crdminio "github.com/3Xpl0it3r/minio-operator/pkg/crd/minio"
+	miniooperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/minio"
+)
+
+// NewRenderCommand return the command which prints the objects the operator creates for a Minio manifest
+func NewRenderCommand() *cobra.Command {
+	opts := options.NewRenderOptions()
+	cmd := &cobra.Command{
+		Use:   "render -f minio.yaml",
+		Short: "Print the resources of a Minio",
+		Long:  "Print the resources the operator creates for a Minio manifest as yaml, nothing is read from or written to the cluster",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.Validate(); len(err) != 0 {
+				return fmt.Errorf("Options validate failed, %v. ", err)
+			}
+			if err := opts.Complete(); err != nil {
+				return fmt.Errorf("Options Complete failed %v. ", err)
+			}
+			return runRender(opts, cmd.OutOrStdout())
+		},
+	}
+	nfs := opts.NamedFlagSets()
+	for _, f := range nfs.FlagSets {
+		cmd.Flags().AddFlagSet(f)
+	}
+	setUsageAndHelp(cmd, nfs)
+	return cmd
+}
+
+func runRender(o *options.RenderOptions, out io.Writer) error {
+	minio, err := readMinio(o.Filename)
+	if err != nil {
+		return fmt.Errorf("read %s failed: %v", o.Filename, err)
+	}
+	if minio.GetNamespace() == "" {
+		minio.SetNamespace(o.Namespace)
+	}
+	nodes := o.Nodes
+	if len(nodes) == 0 {
+		replicas := int(minio.Spec.Replicas)
+		if replicas == 0 {
+			replicas = 1
+		}
+		for index := 0; index < replicas; index++ {
+			nodes = append(nodes, fmt.Sprintf("node-%d", index))
+		}
+	}
+	objects, err := miniooperator.Render(minio, nodes)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		if _, err = fmt.Fprintf(out, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readMinio read a Minio manifest in yaml or json, v1beta1 manifests are converted to v1alpha1 which the operator
+// works with
+func readMinio(filename string) (*crapiv1alpha1.Minio, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if filename == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if data, err = yaml.YAMLToJSON(data); err != nil {
+		return nil, err
+	}
+	var typeMeta metav1.TypeMeta
+	if err = json.Unmarshal(data, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.APIVersion != crapiv1alpha1.SchemeGroupVersion.String() {
+		if data, err = crdminio.ConvertMinio(data, crapiv1alpha1.SchemeGroupVersion.String()); err != nil {
+			return nil, err
+		}
+	}
+	minio := new(crapiv1alpha1.Minio)
+	if err = json.Unmarshal(data, minio); err != nil {
+		return nil, err
+	}
+	return minio, nil
+}
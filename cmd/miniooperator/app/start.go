@@ -31,12 +31,20 @@ import (
 	crinformers "github.com/3Xpl0it3r/minio-operator/pkg/client/informers/externalversions"
 	"github.com/3Xpl0it3r/minio-operator/pkg/controller"
 	"github.com/3Xpl0it3r/minio-operator/pkg/controller/minio"
+	"github.com/3Xpl0it3r/minio-operator/pkg/controller/miniobackup"
+	"github.com/3Xpl0it3r/minio-operator/pkg/controller/miniobackupschedule"
+	"github.com/3Xpl0it3r/minio-operator/pkg/controller/miniorestore"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
-	apicorev1 "k8s.io/api/core/v1"
+	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/informers"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	cliflag "k8s.io/component-base/cli/flag"
@@ -47,6 +55,7 @@ import (
 func NewStartCommand(stopCh <-chan struct{}) *cobra.Command {
 	opts := options.NewOptions()
 	cmd := &cobra.Command{
+		Use:   "miniooperator",
 		Short: "Launch minio-operator",
 		Long:  "Launch minio-operator",
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -71,6 +80,13 @@ func NewStartCommand(stopCh <-chan struct{}) *cobra.Command {
 	klog.InitFlags(local)
 	nfs.FlagSet("logging").AddGoFlagSet(local)
 
+	setUsageAndHelp(cmd, nfs)
+	cmd.AddCommand(NewRenderCommand(), NewCRDCommand())
+	return cmd
+}
+
+// setUsageAndHelp print the flags of cmd grouped by nfs, subcommands set their own flag sets
+func setUsageAndHelp(cmd *cobra.Command, nfs cliflag.NamedFlagSets) {
 	usageFmt := "Usage:\n  %s\n"
 	cols, _, _ := term.TerminalSize(cmd.OutOrStdout())
 	cmd.SetUsageFunc(func(cmd *cobra.Command) error {
@@ -82,14 +98,14 @@ func NewStartCommand(stopCh <-chan struct{}) *cobra.Command {
 		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\n"+usageFmt, cmd.Long, cmd.UseLine())
 		cliflag.PrintSections(cmd.OutOrStdout(), nfs, cols)
 	})
-	return cmd
 }
 
 func runCommand(o *options.Options, signalCh <-chan struct{}) error {
 	install.Install(scheme.Scheme)
+	// event recorders resolve the kind of involved objects from the client-go scheme
+	install.Install(kubescheme.Scheme)
 
 	var err error
-	var stopCh = make(chan struct{})
 	restConfig, err := buildKubeConfig("", "")
 	if err != nil {
 		return fmt.Errorf("build kubeConfig failed: %v", err)
@@ -98,11 +114,46 @@ func runCommand(o *options.Options, signalCh <-chan struct{}) error {
 	if err != nil {
 		return fmt.Errorf("builde extclient failed: %v", err)
 	}
-	if err := crd.InstallCustomResourceDefineToApiServer(extClientSet); err != nil {
-		if !k8serror.IsAlreadyExists(err) {
-			return fmt.Errorf("Install crd failed: %v", err)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	health := newHealthChecker(o.LivenessStallTimeout)
+	srv, err := startWebServer(o.ListenAddress, reg, health)
+	if err != nil {
+		return fmt.Errorf("start web server failed: %v", err)
+	}
+	defer srv.Close()
+
+	crClientSet, err := crclientset.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("build crClientSet failed: %v", err)
+	}
+
+	// the conversion webhook is served by all replicas, apiserver calls it for every request of v1beta1 once it is
+	// the storage version
+	var conversion *extensionapiv1.WebhookClientConfig
+	if o.Webhook.Enabled() {
+		webhookSrv, err := startWebhookServer(o.Webhook)
+		if err != nil {
+			return fmt.Errorf("start webhook server failed: %v", err)
+		}
+		defer webhookSrv.Close()
+		if conversion, err = webhookClientConfig(o.Webhook); err != nil {
+			return fmt.Errorf("build webhook client config failed: %v", err)
+		}
+	}
+	// without --install-crds the crd are managed outside, the operator does not touch them and is ready once the
+	// informers have synced
+	if o.InstallCRDs {
+		if err := crd.InstallCustomResourceDefineToApiServer(extClientSet, conversion); err != nil {
+			if !k8serror.IsAlreadyExists(err) {
+				return fmt.Errorf("Install crd failed: %v", err)
+			}
+		}
+		if err := crd.WaitForCustomResourceDefineEstablished(extClientSet); err != nil {
+			return fmt.Errorf("Wait crd established failed: %v", err)
 		}
 	}
+	health.setCRDEstablished()
 	// should not delete crd
 	// defer crd.UnInstallCustomResourceDefineToApiServer(extClientSet)
 
@@ -110,41 +161,105 @@ func runCommand(o *options.Options, signalCh <-chan struct{}) error {
 	if err != nil {
 		return fmt.Errorf("build kubeClientSet faild: %v", err)
 	}
-	crClientSet, err := crclientset.NewForConfig(restConfig)
+	// dynamicClient manages objects of optional crds, e.g. ServiceMonitor of prometheus operator
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
-		return fmt.Errorf("build crClientSet failed: %v", err)
+		return fmt.Errorf("build dynamicClient failed: %v", err)
 	}
-	crInformers := buildCustomResourceInformerFactory(crClientSet)
-	kubeInformers := buildKubeStandardResourceInformerFactory(kubeClientSet)
 
-	minioController := minio.NewController(kubeClientSet, kubeInformers, crClientSet, crInformers, nil)
+	run := func(stopCh <-chan struct{}) error {
+		if o.InstallCRDs && conversion != nil {
+			// a failed migration is retried by the next leader, objects are still readable in both versions
+			if err := crd.MigrateMinioStorage(extClientSet, kubeClientSet, crClientSet); err != nil {
+				klog.Errorf("migrate minio storage failed: %v", err)
+			}
+		}
+		return runOperator(o, kubeClientSet, crClientSet, dynamicClient, reg, health, stopCh)
+	}
+	if !o.LeaderElection.LeaderElect {
+		return run(signalCh)
+	}
+	// only the leader migrates storage and starts informers and controller, the others wait for the lease
+	return runWithLeaderElection(o.LeaderElection, kubeClientSet, signalCh, run)
+}
+
+// runOperator start all informers and controller, and block until leaderCh is closed
+func runOperator(o *options.Options, kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, dynamicClient dynamic.Interface, reg prometheus.Registerer, health *healthChecker, leaderCh <-chan struct{}) error {
+	var stopCh = make(chan struct{})
+
+	clusterInformers := informers.NewSharedInformerFactory(kubeClientSet, o.ResyncPeriod)
+	crInformers := map[string]crinformers.SharedInformerFactory{}
+	kubeInformers := map[string]informers.SharedInformerFactory{}
+	for _, namespace := range o.Namespaces {
+		crInformers[namespace] = buildCustomResourceInformerFactory(crClientSet, namespace, o.MinioSelector, o.ResyncPeriod)
+		kubeInformers[namespace] = buildKubeStandardResourceInformerFactory(kubeClientSet, namespace, o.ResyncPeriod)
+	}
+
+	// minio controller must be created first, it registers the workqueue metrics provider
+	controllers := []controller.Controller{
+		minio.NewController(kubeClientSet, clusterInformers, kubeInformers, crClientSet, crInformers, dynamicClient, o.ResyncPeriod, reg),
+		miniobackup.NewController(kubeClientSet, crClientSet, crInformers, o.ResyncPeriod),
+		miniobackupschedule.NewController(kubeClientSet, crClientSet, crInformers, o.ResyncPeriod),
+		miniorestore.NewController(kubeClientSet, crClientSet, crInformers, o.ResyncPeriod),
+	}
+	health.setControllers(controllers)
+	defer health.setControllers(nil)
 
-	crInformers.Start(stopCh)
-	kubeInformers.Start(stopCh)
+	clusterInformers.Start(stopCh)
+	for _, factory := range crInformers {
+		factory.Start(stopCh)
+	}
+	for _, factory := range kubeInformers {
+		factory.Start(stopCh)
+	}
 
-	if err := runController(stopCh, minioController); err != nil {
-		return fmt.Errorf("run controller failed: %v", err)
+	for _, c := range controllers {
+		if err := runController(stopCh, c, o.Workers); err != nil {
+			close(stopCh)
+			return fmt.Errorf("run controller failed: %v", err)
+		}
 	}
 
 	select {
-	case <-signalCh:
+	case <-leaderCh:
 		klog.Infof("exited")
 		close(stopCh)
 	case <-stopCh:
 	}
 
-	minioController.Stop()
+	for _, c := range controllers {
+		c.Stop()
+	}
 
 	return nil
 }
 
-func runController(stopCh <-chan struct{}, controller controller.Controller) error {
-	if err := controller.Start(1, stopCh); err != nil {
+func runController(stopCh <-chan struct{}, controller controller.Controller, workers int) error {
+	if err := controller.Start(workers, stopCh); err != nil {
 		return err
 	}
 	return nil
 }
 
+// startWebServer serve metrics and health probes on listenAddress
+func startWebServer(listenAddress string, reg *prometheus.Registry, health *healthChecker) (*http.Server, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{Registry: reg}))
+	mux.Handle("/healthz", probeHandler(health.live))
+	mux.Handle("/readyz", probeHandler(health.ready))
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := serve(srv, listener)(); err != nil {
+			klog.Errorf("web server exited: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
 func serve(srv *http.Server, listener net.Listener) func() error {
 	return func() error {
 		if err := srv.Serve(listener); err != http.ErrServerClosed {
@@ -181,17 +296,19 @@ func buildKubeConfig(masterUrl, kubeConfig string) (*rest.Config, error) {
 }
 
 // buildCustomResourceInformerFactory build crd informer factory according some options
-func buildCustomResourceInformerFactory(crClient crclientset.Interface) crinformers.SharedInformerFactory {
+func buildCustomResourceInformerFactory(crClient crclientset.Interface, namespace string, selector string, resyncPeriod time.Duration) crinformers.SharedInformerFactory {
 	var factoryOpts []crinformers.SharedInformerOption
-	factoryOpts = append(factoryOpts, crinformers.WithNamespace(apicorev1.NamespaceAll))
-	factoryOpts = append(factoryOpts, crinformers.WithTweakListOptions(func(listOptions *v1.ListOptions) {}))
-	return crinformers.NewSharedInformerFactoryWithOptions(crClient, 5*time.Second, factoryOpts...)
+	factoryOpts = append(factoryOpts, crinformers.WithNamespace(namespace))
+	factoryOpts = append(factoryOpts, crinformers.WithTweakListOptions(func(listOptions *v1.ListOptions) {
+		listOptions.LabelSelector = selector
+	}))
+	return crinformers.NewSharedInformerFactoryWithOptions(crClient, resyncPeriod, factoryOpts...)
 }
 
 // buildKubeStandardResourceInformerFactory build a kube informer factory according some options
-func buildKubeStandardResourceInformerFactory(kubeClient kubernetes.Interface) informers.SharedInformerFactory {
+func buildKubeStandardResourceInformerFactory(kubeClient kubernetes.Interface, namespace string, resyncPeriod time.Duration) informers.SharedInformerFactory {
 	var factoryOpts []informers.SharedInformerOption
-	factoryOpts = append(factoryOpts, informers.WithNamespace(apicorev1.NamespaceAll))
+	factoryOpts = append(factoryOpts, informers.WithNamespace(namespace))
 	factoryOpts = append(factoryOpts, informers.WithTweakListOptions(func(listOptions *v1.ListOptions) {}))
-	return informers.NewSharedInformerFactoryWithOptions(kubeClient, 5*time.Second, factoryOpts...)
+	return informers.NewSharedInformerFactoryWithOptions(kubeClient, resyncPeriod, factoryOpts...)
 }
@@ -0,0 +1,116 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	"github.com/3Xpl0it3r/minio-operator/cmd/miniooperator/options"
+	crdminio "github.com/3Xpl0it3r/minio-operator/pkg/crd/minio"
+)
+
+// conversionPath is the path of the crd conversion webhook
+const conversionPath = "/convert"
+
+// startWebhookServer serve the crd conversion webhook with the certificate in the cert dir
+func startWebhookServer(o *options.WebhookOptions) (*http.Server, error) {
+	certificate, err := tls.LoadX509KeyPair(filepath.Join(o.CertDir, "tls.crt"), filepath.Join(o.CertDir, "tls.key"))
+	if err != nil {
+		return nil, fmt.Errorf("load webhook certificate failed: %v", err)
+	}
+	listener, err := net.Listen("tcp", o.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(conversionPath, http.HandlerFunc(convertHandler))
+	srv := &http.Server{Handler: mux, TLSConfig: &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}}
+	go func() {
+		if err := serveTLS(srv, listener)(); err != nil {
+			klog.Errorf("webhook server exited: %v", err)
+		}
+	}()
+	return srv, nil
+}
+
+// webhookClientConfig return how the apiserver reaches the conversion webhook, ca.crt is the caBundle if it exists,
+// otherwise the certificate of webhook is trusted directly
+func webhookClientConfig(o *options.WebhookOptions) (*extensionapiv1.WebhookClientConfig, error) {
+	caBundle, err := os.ReadFile(filepath.Join(o.CertDir, "ca.crt"))
+	if os.IsNotExist(err) {
+		caBundle, err = os.ReadFile(filepath.Join(o.CertDir, "tls.crt"))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newWebhookClientConfig(o.ServiceNamespace, o.ServiceName, o.ServicePort, caBundle), nil
+}
+
+func newWebhookClientConfig(namespace, name string, port int32, caBundle []byte) *extensionapiv1.WebhookClientConfig {
+	path := conversionPath
+	return &extensionapiv1.WebhookClientConfig{
+		Service: &extensionapiv1.ServiceReference{
+			Namespace: namespace,
+			Name:      name,
+			Path:      &path,
+			Port:      &port,
+		},
+		CABundle: caBundle,
+	}
+}
+
+// convertHandler serve the ConversionReview of apiserver, all objects are converted or the review fails
+func convertHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("read request failed: %v", err), http.StatusBadRequest)
+		return
+	}
+	review := new(extensionapiv1.ConversionReview)
+	if err = json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid conversion review: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	response := &extensionapiv1.ConversionResponse{UID: review.Request.UID, Result: metav1.Status{Status: metav1.StatusSuccess}}
+	for _, object := range review.Request.Objects {
+		converted, err := crdminio.ConvertMinio(object.Raw, review.Request.DesiredAPIVersion)
+		if err != nil {
+			klog.Errorf("convert minio to %s failed: %v", review.Request.DesiredAPIVersion, err)
+			response.ConvertedObjects = nil
+			response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			break
+		}
+		response.ConvertedObjects = append(response.ConvertedObjects, runtime.RawExtension{Raw: converted})
+	}
+	review.Request = nil
+	review.Response = response
+
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(review); err != nil {
+		klog.Errorf("write conversion review failed: %v", err)
+	}
+}
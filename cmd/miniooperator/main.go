@@ -15,7 +15,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 
 	"github.com/3Xpl0it3r/minio-operator/cmd/miniooperator/app"
 
@@ -0,0 +1,86 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/cli/flag"
+)
+
+// CRDOptions represent the options of the crd command
+type CRDOptions struct {
+	// Kubeconfig is the kubeconfig file used by install and uninstall, the default loading rules are used if empty
+	Kubeconfig string
+	// ConversionWebhook serves v1beta1 of Minio, objects are converted by the webhook of the operator
+	ConversionWebhook bool
+	// WebhookCAFile is the caBundle which verifies the certificate of the webhook
+	WebhookCAFile string
+	// WebhookServiceName is the name of the service in front of the webhook
+	WebhookServiceName string
+	// WebhookServiceNamespace is the namespace of the service
+	WebhookServiceNamespace string
+	// WebhookServicePort is the port of the service
+	WebhookServicePort int32
+}
+
+var _ options = new(CRDOptions)
+
+// NewCRDOptions create crd options with default value, the webhook service is the same as the default of operator
+func NewCRDOptions() *CRDOptions {
+	return &CRDOptions{
+		WebhookServiceName:      "clickpaas-operator-minio",
+		WebhookServiceNamespace: "default",
+		WebhookServicePort:      443,
+	}
+}
+
+// Validate validates crd options
+func (o *CRDOptions) Validate() []error {
+	if !o.ConversionWebhook {
+		return nil
+	}
+	var errs []error
+	if o.WebhookCAFile == "" {
+		errs = append(errs, fmt.Errorf("--webhook-ca-file is required by --conversion-webhook"))
+	}
+	if o.WebhookServiceName == "" || o.WebhookServiceNamespace == "" {
+		errs = append(errs, fmt.Errorf("--webhook-service-name and --webhook-service-namespace must not be empty"))
+	}
+	if o.WebhookServicePort < 1 || o.WebhookServicePort > 65535 {
+		errs = append(errs, fmt.Errorf("--webhook-service-port must be between 1 and 65535, got %d", o.WebhookServicePort))
+	}
+	return errs
+}
+
+// Complete does nothing, all options have default value
+func (o *CRDOptions) Complete() error {
+	return nil
+}
+
+// AddFlags add crd flags to fs
+func (o *CRDOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "Path to the kubeconfig file used by install and uninstall, $KUBECONFIG or in cluster config is used if empty")
+	fs.BoolVar(&o.ConversionWebhook, "conversion-webhook", o.ConversionWebhook, "Serve v1beta1 of Minio through the conversion webhook of the operator, the operator must run with --webhook-listen-addr")
+	fs.StringVar(&o.WebhookCAFile, "webhook-ca-file", o.WebhookCAFile, "File of the ca certificate which verifies the conversion webhook")
+	fs.StringVar(&o.WebhookServiceName, "webhook-service-name", o.WebhookServiceName, "Name of the service of the conversion webhook")
+	fs.StringVar(&o.WebhookServiceNamespace, "webhook-service-namespace", o.WebhookServiceNamespace, "Namespace of the service of the conversion webhook")
+	fs.Int32Var(&o.WebhookServicePort, "webhook-service-port", o.WebhookServicePort, "Port of the service of the conversion webhook")
+}
+
+func (o *CRDOptions) NamedFlagSets() (fs flag.NamedFlagSets) {
+	o.AddFlags(fs.FlagSet("crd"))
+	return fs
+}
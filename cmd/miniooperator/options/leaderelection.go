@@ -0,0 +1,98 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// inClusterNamespaceFile is the namespace of the service account mounted into the operator pod
+const inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// LeaderElectionOptions represent the options of the Lease based leader election
+type LeaderElectionOptions struct {
+	// LeaderElect enable leader election, only the leader runs the controller
+	LeaderElect bool
+	// LeaseName is the name of the Lease object used for locking
+	LeaseName string
+	// LeaseNamespace is the namespace of the Lease object, default to the namespace of the operator pod
+	LeaseNamespace string
+	// LeaseDuration is the duration that non-leader candidates will wait before trying to acquire the lease
+	LeaseDuration time.Duration
+	// RenewDeadline is the duration that the leader will retry refreshing leadership before giving up
+	RenewDeadline time.Duration
+	// RetryPeriod is the duration the clients should wait between tries of actions
+	RetryPeriod time.Duration
+}
+
+var _ options = new(LeaderElectionOptions)
+
+// NewLeaderElectionOptions create leader election options with default value
+func NewLeaderElectionOptions() *LeaderElectionOptions {
+	return &LeaderElectionOptions{
+		LeaderElect:   false,
+		LeaseName:     "minio-operator",
+		LeaseDuration: 15 * time.Second,
+		RenewDeadline: 10 * time.Second,
+		RetryPeriod:   2 * time.Second,
+	}
+}
+
+// Validate validates leader election options
+func (o *LeaderElectionOptions) Validate() []error {
+	if !o.LeaderElect {
+		return nil
+	}
+	var errs []error
+	if o.LeaseName == "" {
+		errs = append(errs, fmt.Errorf("--leader-elect-lease-name must not be empty"))
+	}
+	if o.LeaseDuration <= o.RenewDeadline {
+		errs = append(errs, fmt.Errorf("--leader-elect-lease-duration(%v) must be greater than --leader-elect-renew-deadline(%v)", o.LeaseDuration, o.RenewDeadline))
+	}
+	if o.RetryPeriod <= 0 {
+		errs = append(errs, fmt.Errorf("--leader-elect-retry-period must be greater than 0"))
+	}
+	return errs
+}
+
+// Complete fill the namespace of lease if it is not set
+func (o *LeaderElectionOptions) Complete() error {
+	if o.LeaseNamespace != "" {
+		return nil
+	}
+	if data, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); namespace != "" {
+			o.LeaseNamespace = namespace
+			return nil
+		}
+	}
+	o.LeaseNamespace = "default"
+	return nil
+}
+
+// AddFlags add leader election flags to fs
+func (o *LeaderElectionOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.LeaderElect, "leader-elect", o.LeaderElect, "Start a leader election client and gain leadership before running the controller, enable this when running multiple replicas")
+	fs.StringVar(&o.LeaseName, "leader-elect-lease-name", o.LeaseName, "Name of the Lease object used for leader election")
+	fs.StringVar(&o.LeaseNamespace, "leader-elect-lease-namespace", o.LeaseNamespace, "Namespace of the Lease object used for leader election, default to the namespace of the operator pod")
+	fs.DurationVar(&o.LeaseDuration, "leader-elect-lease-duration", o.LeaseDuration, "Duration that non-leader candidates will wait after observing a leadership renewal until attempting to acquire leadership")
+	fs.DurationVar(&o.RenewDeadline, "leader-elect-renew-deadline", o.RenewDeadline, "Duration that the leader will retry refreshing leadership before giving up")
+	fs.DurationVar(&o.RetryPeriod, "leader-elect-retry-period", o.RetryPeriod, "Duration the clients should wait between attempting acquisition and renewal of a leadership")
+}
@@ -14,50 +14,112 @@
 package options
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/component-base/cli/flag"
 )
 
 type Options struct {
 	// this is example flags
 	ListenAddress string
-	// todo write your flags here
+	// Workers is the number of workers which process minio concurrently
+	Workers int
+	// ResyncPeriod is the resync period of all informers
+	ResyncPeriod time.Duration
+	// Namespaces is the list of namespaces watched by the operator, empty means all namespaces
+	Namespaces []string
+	// MinioSelector is a label selector, only minio objects matched by it are owned by this operator instance
+	MinioSelector string
+	// InstallCRDs creates or updates the crd at startup, the operator needs no customresourcedefinitions permission
+	// if it is disabled
+	InstallCRDs bool
+	// LivenessStallTimeout is how long the workqueue may have pending work without progress before liveness probe fails
+	LivenessStallTimeout time.Duration
+
+	LeaderElection *LeaderElectionOptions
+	Webhook        *WebhookOptions
 }
 
 var _ options = new(Options)
 
 // NewOptions create an instance option and return
 func NewOptions() *Options {
-	// todo write your code or change this code here
-	return &Options{}
+	return &Options{
+		Workers:      1,
+		ResyncPeriod: 5 * time.Second,
+		InstallCRDs:  true,
+
+		LivenessStallTimeout: 5 * time.Minute,
+
+		LeaderElection: NewLeaderElectionOptions(),
+		Webhook:        NewWebhookOptions(),
+	}
 }
 
 // Validate validates options
 func (o *Options) Validate() []error {
-	// todo write your code here, if you need some validation
-	return nil
+	var errs []error
+	if o.Workers < 1 {
+		errs = append(errs, fmt.Errorf("--workers must be greater than 0, got %d", o.Workers))
+	}
+	if o.ResyncPeriod < 0 {
+		errs = append(errs, fmt.Errorf("--resync-period must not be negative, got %v", o.ResyncPeriod))
+	}
+	if _, err := labels.Parse(o.MinioSelector); err != nil {
+		errs = append(errs, fmt.Errorf("--minio-selector is invalid: %v", err))
+	}
+	if o.LivenessStallTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--liveness-stall-timeout must be greater than 0, got %v", o.LivenessStallTimeout))
+	}
+	errs = append(errs, o.LeaderElection.Validate()...)
+	errs = append(errs, o.Webhook.Validate()...)
+	return errs
 }
 
 // Complete fill some default value to options
 func (o *Options) Complete() error {
-	// todo write your code here, you may do some defaulter if neceressary
-	return nil
+	// remove duplicated namespaces, if no namespace is given or any of them is NamespaceAll, then watch all namespaces
+	seen := map[string]bool{}
+	namespaces := []string{}
+	for _, namespace := range o.Namespaces {
+		if namespace == apicorev1.NamespaceAll {
+			namespaces = nil
+			break
+		}
+		if !seen[namespace] {
+			seen[namespace] = true
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	if len(namespaces) == 0 {
+		namespaces = []string{apicorev1.NamespaceAll}
+	}
+	o.Namespaces = namespaces
+	if err := o.Webhook.Complete(); err != nil {
+		return err
+	}
+	return o.LeaderElection.Complete()
 }
 
 //
 func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&o.ListenAddress, "web.listen-addr", ":8080", "Address on which to expose metrics and web interfaces")
-	// todo write your code here
-    
+	fs.IntVar(&o.Workers, "workers", o.Workers, "Number of minio objects which are reconciled concurrently")
+	fs.DurationVar(&o.ResyncPeriod, "resync-period", o.ResyncPeriod, "Resync period of the informers")
+	fs.StringSliceVar(&o.Namespaces, "namespaces", o.Namespaces, "Comma separated list of namespaces watched by the operator, all namespaces are watched if empty")
+	fs.DurationVar(&o.LivenessStallTimeout, "liveness-stall-timeout", o.LivenessStallTimeout, "Liveness probe fails if the workqueue has pending work but has not made progress for this period")
+	fs.BoolVar(&o.InstallCRDs, "install-crds", o.InstallCRDs, "Create or update the crd at startup, disable it if the crd are installed by the crd command or a pipeline")
+	fs.StringVar(&o.MinioSelector, "minio-selector", o.MinioSelector, "Label selector of the minio objects owned by this operator instance, all minio objects are owned if empty")
 }
 
 func (o *Options) NamedFlagSets() (fs flag.NamedFlagSets) {
 	o.AddFlags(fs.FlagSet("minio-operator"))
-
-    // other Addflags
-    // o.otherConfig.AddFlahs(fs.FlagSet(""xxxxx))
-
-
+	o.LeaderElection.AddFlags(fs.FlagSet("leader-election"))
+	o.Webhook.AddFlags(fs.FlagSet("webhook"))
 
 	return fs
 }
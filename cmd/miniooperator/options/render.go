@@ -0,0 +1,76 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+	"k8s.io/component-base/cli/flag"
+)
+
+// RenderOptions represent the options of the render command
+type RenderOptions struct {
+	// Filename is the Minio manifest, - means stdin
+	Filename string
+	// Namespace is used if the manifest has no namespace
+	Namespace string
+	// Nodes are the names of ready nodes members are placed on, one node per member is simulated if it is empty
+	Nodes []string
+}
+
+var _ options = new(RenderOptions)
+
+// NewRenderOptions create render options with default value
+func NewRenderOptions() *RenderOptions {
+	return &RenderOptions{Namespace: "default"}
+}
+
+// Validate validates render options
+func (o *RenderOptions) Validate() []error {
+	var errs []error
+	if o.Filename == "" {
+		errs = append(errs, fmt.Errorf("-f/--filename is required"))
+	}
+	if o.Namespace == "" {
+		errs = append(errs, fmt.Errorf("--namespace must not be empty"))
+	}
+	return errs
+}
+
+// Complete remove duplicated nodes, a node is only picked once per level by the operator
+func (o *RenderOptions) Complete() error {
+	seen := map[string]bool{}
+	nodes := []string{}
+	for _, node := range o.Nodes {
+		if node != "" && !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+	o.Nodes = nodes
+	return nil
+}
+
+// AddFlags add render flags to fs
+func (o *RenderOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&o.Filename, "filename", "f", o.Filename, "Minio manifest to render, v1alpha1 and v1beta1 are accepted, - reads from stdin")
+	fs.StringVarP(&o.Namespace, "namespace", "n", o.Namespace, "Namespace of the Minio if the manifest has no namespace")
+	fs.StringSliceVar(&o.Nodes, "nodes", o.Nodes, "Comma separated names of the ready nodes members are placed on, one node per member is simulated if empty")
+}
+
+func (o *RenderOptions) NamedFlagSets() (fs flag.NamedFlagSets) {
+	o.AddFlags(fs.FlagSet("render"))
+	return fs
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+)
+
+// WebhookOptions represent the options of the crd conversion webhook, v1beta1 of Minio is served only if the webhook
+// is enabled
+type WebhookOptions struct {
+	// ListenAddress is the address the webhook listens on, the webhook is disabled if it is empty
+	ListenAddress string
+	// CertDir holds tls.crt and tls.key of the webhook, and ca.crt which is the caBundle of the crd
+	CertDir string
+	// ServiceName is the name of the service in front of the webhook
+	ServiceName string
+	// ServiceNamespace is the namespace of the service, default to the namespace of the operator pod
+	ServiceNamespace string
+	// ServicePort is the port of the service which is forwarded to ListenAddress
+	ServicePort int32
+}
+
+var _ options = new(WebhookOptions)
+
+// NewWebhookOptions create webhook options with default value
+func NewWebhookOptions() *WebhookOptions {
+	return &WebhookOptions{
+		CertDir:     "/etc/minio-operator/webhook",
+		ServiceName: "clickpaas-operator-minio",
+		ServicePort: 443,
+	}
+}
+
+// Enabled return true if the conversion webhook should be started
+func (o *WebhookOptions) Enabled() bool {
+	return o.ListenAddress != ""
+}
+
+// Validate validates webhook options
+func (o *WebhookOptions) Validate() []error {
+	if !o.Enabled() {
+		return nil
+	}
+	var errs []error
+	if o.CertDir == "" {
+		errs = append(errs, fmt.Errorf("--webhook-cert-dir must not be empty"))
+	}
+	if o.ServiceName == "" {
+		errs = append(errs, fmt.Errorf("--webhook-service-name must not be empty"))
+	}
+	if o.ServicePort < 1 || o.ServicePort > 65535 {
+		errs = append(errs, fmt.Errorf("--webhook-service-port must be between 1 and 65535, got %d", o.ServicePort))
+	}
+	return errs
+}
+
+// Complete fill the namespace of service if it is not set
+func (o *WebhookOptions) Complete() error {
+	if o.ServiceNamespace != "" {
+		return nil
+	}
+	if data, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); namespace != "" {
+			o.ServiceNamespace = namespace
+			return nil
+		}
+	}
+	o.ServiceNamespace = "default"
+	return nil
+}
+
+// AddFlags add webhook flags to fs
+func (o *WebhookOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ListenAddress, "webhook-listen-addr", o.ListenAddress, "Address on which to serve the crd conversion webhook, v1beta1 of Minio is served only if it is set")
+	fs.StringVar(&o.CertDir, "webhook-cert-dir", o.CertDir, "Directory holding tls.crt, tls.key and ca.crt of the conversion webhook, tls.crt is used as caBundle if ca.crt does not exist")
+	fs.StringVar(&o.ServiceName, "webhook-service-name", o.ServiceName, "Name of the service which the apiserver reaches the conversion webhook through")
+	fs.StringVar(&o.ServiceNamespace, "webhook-service-namespace", o.ServiceNamespace, "Namespace of the webhook service, default to the namespace of the operator pod")
+	fs.Int32Var(&o.ServicePort, "webhook-service-port", o.ServicePort, "Port of the webhook service")
+}
@@ -0,0 +1,149 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioBackup defines a point-in-time backup of buckets of a Minio
+type MinioBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinioBackupSpec   `json:"spec"`
+	Status MinioBackupStatus `json:"status"`
+}
+
+// MinioBackupSpec describes which buckets are backed up and where the backup is stored
+type MinioBackupSpec struct {
+	// Minio is the name of the Minio to back up, it must be in the same namespace as the backup
+	Minio string `json:"minio"`
+	// Buckets to back up, all buckets are backed up if it is empty
+	Buckets []string `json:"buckets,omitempty"`
+	// Target is where the backup is stored
+	Target BackupTarget `json:"target"`
+}
+
+// BackupTarget is the storage of backups, exactly one of S3 and PersistentVolumeClaim should be set
+type BackupTarget struct {
+	S3                    *S3BackupTarget  `json:"s3,omitempty"`
+	PersistentVolumeClaim *PVCBackupTarget `json:"persistentVolumeClaim,omitempty"`
+}
+
+// S3BackupTarget is a bucket of an S3 compatible endpoint
+type S3BackupTarget struct {
+	// Endpoint is host[:port] of the S3 service
+	Endpoint string `json:"endpoint"`
+	Bucket   string `json:"bucket"`
+	// Prefix is prepended to the key of all objects written by backups
+	Prefix string `json:"prefix,omitempty"`
+	Region string `json:"region,omitempty"`
+	// Secure is true if the endpoint is served over https
+	Secure bool `json:"secure,omitempty"`
+	// CredentialSecret is the name of the secret holding accessKey and secretKey of the endpoint
+	CredentialSecret string `json:"credentialSecret"`
+}
+
+// PVCBackupTarget is a persistent volume claim, it is served by a temporary minio pod while backups are running
+type PVCBackupTarget struct {
+	ClaimName string `json:"claimName"`
+	// Image of the temporary minio pod, the image of the source Minio is used if it is empty
+	Image string `json:"image,omitempty"`
+}
+
+// BackupPhase is the phase of a backup
+type BackupPhase string
+
+const (
+	BackupPhasePending   BackupPhase = "Pending"
+	BackupPhaseRunning   BackupPhase = "Running"
+	BackupPhaseCompleted BackupPhase = "Completed"
+	BackupPhaseFailed    BackupPhase = "Failed"
+)
+
+// MinioBackupStatus describes the progress and result of a backup
+type MinioBackupStatus struct {
+	Phase          BackupPhase  `json:"phase,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Location is where the backup data is stored
+	Location string `json:"location,omitempty"`
+	// Base is the completed backup on the same target which unchanged objects are copied from
+	Base string `json:"base,omitempty"`
+	// Buckets is the buckets which are backed up
+	Buckets []string `json:"buckets,omitempty"`
+	// ObjectsCopied is the number of objects read from the source Minio
+	ObjectsCopied int64 `json:"objectsCopied,omitempty"`
+	// ObjectsSkipped is the number of unchanged objects copied from the base backup inside the target
+	ObjectsSkipped int64 `json:"objectsSkipped,omitempty"`
+	// BytesCopied is the size of objects read from the source Minio
+	BytesCopied int64  `json:"bytesCopied,omitempty"`
+	Message     string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioBackupList carries a list of MinioBackup objects
+type MinioBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MinioBackup `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioBackupSchedule creates MinioBackup periodically
+type MinioBackupSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinioBackupScheduleSpec   `json:"spec"`
+	Status MinioBackupScheduleStatus `json:"status"`
+}
+
+// MinioBackupScheduleSpec describes when backups are created and how many of them are kept
+type MinioBackupScheduleSpec struct {
+	// Schedule is a cron expression in the standard five fields format
+	Schedule string `json:"schedule"`
+	// Suspend stops creating new backups, existing backups are not affected
+	Suspend bool `json:"suspend,omitempty"`
+	// Retention is the number of completed backups to keep, older backups and their data are deleted. 0 keeps all backups
+	Retention int32 `json:"retention,omitempty"`
+	// Template is the spec of backups created by the schedule
+	Template MinioBackupSpec `json:"template"`
+}
+
+// MinioBackupScheduleStatus describes the last backup created by the schedule
+type MinioBackupScheduleStatus struct {
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	LastBackup       string       `json:"lastBackup,omitempty"`
+	// LastSuccessfulBackup is the latest completed backup created by the schedule
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioBackupScheduleList carries a list of MinioBackupSchedule objects
+type MinioBackupScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MinioBackupSchedule `json:"items"`
+}
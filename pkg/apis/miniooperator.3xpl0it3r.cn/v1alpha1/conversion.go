@@ -0,0 +1,168 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn"
+	"github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
+)
+
+// annotations which keep the fields that only exist in one version, so that objects survive a round trip
+const (
+	// CredentialSecretAnnotation is v1beta1 spec.credential.secretName, the operator reads the credential from it
+	CredentialSecretAnnotation = miniooperator.GroupName + "/credential-secret"
+	// InlineCredentialAnnotation is the v1alpha1 spec.credential of objects written by earlier operators, it is only
+	// read so that storage migration can move the credential into a secret
+	InlineCredentialAnnotation = miniooperator.GroupName + "/v1alpha1-credential"
+)
+
+// ConvertTo converts this Minio to the hub version v1beta1
+func (src *Minio) ConvertTo(dst *v1beta1.Minio) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = v1beta1.SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	annotations := copyAnnotations(src.GetAnnotations())
+
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Image = src.Spec.Image
+	dst.Spec.HostPath = src.Spec.HostPath
+	dst.Spec.Buckets = append([]string(nil), src.Spec.Buckets...)
+	// v1beta1 does not hold the credential, an inline credential is only dropped if it is the copy resolved from the
+	// referenced secret, the others must be moved into a secret by storage migration first
+	dst.Spec.Credential = v1beta1.CredentialReference{SecretName: annotations[CredentialSecretAnnotation]}
+	delete(annotations, CredentialSecretAnnotation)
+	if src.Spec.Credential != (Credential{}) && dst.Spec.Credential.SecretName == "" {
+		return fmt.Errorf("minio %s/%s: spec.credential can not be stored as %s, put it in a secret and set annotation %s",
+			src.GetNamespace(), src.GetName(), v1beta1.Version, CredentialSecretAnnotation)
+	}
+	dst.Spec.Ports = v1beta1.MinioPorts{API: src.Spec.Port.HttpPort, Console: src.Spec.Port.ApiPort, NodePort: src.Spec.Port.NodePort}
+	dst.SetAnnotations(nilIfEmpty(annotations))
+
+	// the other sections have the same shape in both versions
+	if err := convertSection(src.Spec.SiteReplication, &dst.Spec.SiteReplication); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Settings, &dst.Spec.Settings); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Env, &dst.Spec.Env); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.EnvFrom, &dst.Spec.EnvFrom); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Console, &dst.Spec.Console); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Identity, &dst.Spec.Identity); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Encryption, &dst.Spec.Encryption); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Logging, &dst.Spec.Logging); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Monitoring, &dst.Spec.Monitoring); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.NetworkPolicy, &dst.Spec.NetworkPolicy); err != nil {
+		return err
+	}
+	return convertSection(src.Status, &dst.Status)
+}
+
+// ConvertFrom converts the hub version v1beta1 to this Minio
+func (dst *Minio) ConvertFrom(src *v1beta1.Minio) error {
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = SchemeGroupVersion.String()
+	src.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	annotations := copyAnnotations(src.GetAnnotations())
+
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Image = src.Spec.Image
+	dst.Spec.HostPath = src.Spec.HostPath
+	dst.Spec.Buckets = append([]string(nil), src.Spec.Buckets...)
+	dst.Spec.Credential = Credential{}
+	if data, ok := annotations[InlineCredentialAnnotation]; ok {
+		if err := json.Unmarshal([]byte(data), &dst.Spec.Credential); err != nil {
+			return err
+		}
+		delete(annotations, InlineCredentialAnnotation)
+	}
+	if src.Spec.Credential.SecretName != "" {
+		annotations[CredentialSecretAnnotation] = src.Spec.Credential.SecretName
+	}
+	dst.Spec.Port = ServicePort{HttpPort: src.Spec.Ports.API, ApiPort: src.Spec.Ports.Console, NodePort: src.Spec.Ports.NodePort}
+	dst.SetAnnotations(nilIfEmpty(annotations))
+
+	if err := convertSection(src.Spec.SiteReplication, &dst.Spec.SiteReplication); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Settings, &dst.Spec.Settings); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Env, &dst.Spec.Env); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.EnvFrom, &dst.Spec.EnvFrom); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Console, &dst.Spec.Console); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Identity, &dst.Spec.Identity); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Encryption, &dst.Spec.Encryption); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Logging, &dst.Spec.Logging); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.Monitoring, &dst.Spec.Monitoring); err != nil {
+		return err
+	}
+	if err := convertSection(src.Spec.NetworkPolicy, &dst.Spec.NetworkPolicy); err != nil {
+		return err
+	}
+	return convertSection(src.Status, &dst.Status)
+}
+
+// convertSection copies in to out through json, in and out must have the same json shape
+func convertSection(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
+
+func copyAnnotations(annotations map[string]string) map[string]string {
+	copied := make(map[string]string, len(annotations))
+	for key, value := range annotations {
+		copied[key] = value
+	}
+	return copied
+}
+
+func nilIfEmpty(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	return annotations
+}
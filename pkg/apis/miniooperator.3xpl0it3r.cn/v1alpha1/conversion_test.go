@@ -0,0 +1,84 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
+)
+
+func TestConvertCredentialRoundTrip(t *testing.T) {
+	src := &Minio{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio", Namespace: "default", Annotations: map[string]string{CredentialSecretAnnotation: "minio-root"}},
+		// the credential resolved from the secret by the operator
+		Spec: MinioSpec{Replicas: 4, Credential: Credential{AccessKey: "access", SecretKey: "s3cr3t-root-password"}},
+	}
+	hub := &v1beta1.Minio{}
+	if err := src.ConvertTo(hub); err != nil {
+		t.Fatalf("ConvertTo failed: %v", err)
+	}
+	if hub.Spec.Credential.SecretName != "minio-root" {
+		t.Errorf("secretName = %q, want minio-root", hub.Spec.Credential.SecretName)
+	}
+	for key, value := range hub.GetAnnotations() {
+		if strings.Contains(value, src.Spec.Credential.SecretKey) {
+			t.Errorf("annotation %s holds the credential: %s", key, value)
+		}
+	}
+
+	dst := &Minio{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom failed: %v", err)
+	}
+	if dst.Spec.Credential != (Credential{}) {
+		t.Errorf("credential = %+v, want empty", dst.Spec.Credential)
+	}
+	if got := dst.GetAnnotations()[CredentialSecretAnnotation]; got != "minio-root" {
+		t.Errorf("annotation %s = %q, want minio-root", CredentialSecretAnnotation, got)
+	}
+	if dst.Spec.Replicas != src.Spec.Replicas {
+		t.Errorf("replicas = %d, want %d", dst.Spec.Replicas, src.Spec.Replicas)
+	}
+}
+
+func TestConvertToRejectsInlineCredential(t *testing.T) {
+	src := &Minio{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio", Namespace: "default"},
+		Spec:       MinioSpec{Credential: Credential{AccessKey: "access", SecretKey: "secret"}},
+	}
+	if err := src.ConvertTo(&v1beta1.Minio{}); err == nil {
+		t.Fatal("ConvertTo of an inline credential without secret succeeded")
+	}
+}
+
+func TestConvertFromLegacyInlineCredential(t *testing.T) {
+	hub := &v1beta1.Minio{
+		ObjectMeta: metav1.ObjectMeta{Name: "minio", Namespace: "default", Annotations: map[string]string{
+			InlineCredentialAnnotation: `{"access_key":"access","secret_key":"secret"}`,
+		}},
+	}
+	dst := &Minio{}
+	if err := dst.ConvertFrom(hub); err != nil {
+		t.Fatalf("ConvertFrom failed: %v", err)
+	}
+	if want := (Credential{AccessKey: "access", SecretKey: "secret"}); dst.Spec.Credential != want {
+		t.Errorf("credential = %+v, want %+v", dst.Spec.Credential, want)
+	}
+	if _, ok := dst.GetAnnotations()[InlineCredentialAnnotation]; ok {
+		t.Errorf("annotation %s is kept", InlineCredentialAnnotation)
+	}
+}
@@ -1,5 +1,11 @@
 package v1alpha1
 
+import (
+    corev1 "k8s.io/api/core/v1"
+    networkingv1 "k8s.io/api/networking/v1"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 func MinioDefaulter(minio *Minio) {
     if minio.Spec.Replicas == 0 {
         minio.Spec.Replicas = 1
@@ -24,4 +30,65 @@ func MinioDefaulter(minio *Minio) {
         minio.Spec.Port.HttpPort = 9000
     }
     // minio.Spec.Port.NodePort is not set default for k8s will allocate a new one for it
+
+    if minio.Spec.SiteReplication != nil {
+        if minio.Spec.SiteReplication.Name == "" {
+            minio.Spec.SiteReplication.Name = minio.GetName()
+        }
+        if minio.Spec.SiteReplication.Image == "" {
+            minio.Spec.SiteReplication.Image = "minio/mc"
+        }
+    }
+    if console := minio.Spec.Console; console != nil {
+        if console.Image == "" {
+            console.Image = "minio/console"
+        }
+        if console.Replicas == 0 {
+            console.Replicas = 1
+        }
+        if console.Port == 0 {
+            console.Port = 9090
+        }
+        if console.ServiceType == "" {
+            console.ServiceType = corev1.ServiceTypeClusterIP
+        }
+    }
+    if encryption := minio.Spec.Encryption; encryption != nil {
+        if encryption.Endpoint == "" && encryption.KES == nil {
+            encryption.KES = &KESSpec{}
+        }
+        if kes := encryption.KES; kes != nil {
+            if kes.Image == "" {
+                kes.Image = "minio/kes"
+            }
+            if kes.Replicas == 0 {
+                kes.Replicas = 1
+            }
+        }
+        for index := range encryption.Buckets {
+            if encryption.Buckets[index].Algorithm == "" {
+                encryption.Buckets[index].Algorithm = BucketEncryptionSSEKMS
+            }
+            if encryption.Buckets[index].Algorithm == BucketEncryptionSSEKMS && encryption.Buckets[index].KeyID == "" {
+                encryption.Buckets[index].KeyID = encryption.KeyName
+            }
+        }
+    }
+    if logging := minio.Spec.Logging; logging != nil && logging.Collector != nil {
+        if logging.Collector.Image == "" {
+            logging.Collector.Image = "fluent/fluent-bit"
+        }
+        if logging.Collector.Size == "" {
+            logging.Collector.Size = "10Gi"
+        }
+    }
+    if monitoring := minio.Spec.Monitoring; monitoring != nil && monitoring.Interval == "" {
+        monitoring.Interval = "30s"
+    }
+    if policy := minio.Spec.NetworkPolicy; policy != nil && len(policy.Operator) == 0 {
+        policy.Operator = []networkingv1.NetworkPolicyPeer{{
+            NamespaceSelector: &metav1.LabelSelector{},
+            PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "clickpaas-operator-minio"}},
+        }}
+    }
 }
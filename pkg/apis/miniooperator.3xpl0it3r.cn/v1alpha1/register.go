@@ -51,7 +51,13 @@ func Kind(kind string) schema.GroupKind {
 func addKnowTypes(scheme *runtime.Scheme) error {
 	scheme.AddKnownTypes(SchemeGroupVersion,
 		new(Minio),
-		new(MinioList))
+		new(MinioList),
+		new(MinioBackup),
+		new(MinioBackupList),
+		new(MinioBackupSchedule),
+		new(MinioBackupScheduleList),
+		new(MinioRestore),
+		new(MinioRestoreList))
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
 }
@@ -0,0 +1,104 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioRestore restores buckets from a backup into a Minio
+type MinioRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   MinioRestoreSpec   `json:"spec"`
+	Status MinioRestoreStatus `json:"status"`
+}
+
+// MinioRestoreSpec describes which backup is restored into which Minio
+type MinioRestoreSpec struct {
+	// Minio is the name of the Minio to restore into, it must be in the same namespace as the restore
+	Minio string `json:"minio"`
+	// Backup is the name of a completed MinioBackup in the same namespace
+	Backup string `json:"backup,omitempty"`
+	// Location locates the backup data directly, it is used when the MinioBackup object does not exist,
+	// e.g. restoring into a new kubernetes cluster. exactly one of Backup and Location should be set
+	Location *RestoreLocation `json:"location,omitempty"`
+	// Buckets to restore, all buckets of the backup are restored if it is empty
+	Buckets []string `json:"buckets,omitempty"`
+	// Overwrite decides what to do with objects which already exist in the Minio, default is Never
+	Overwrite OverwritePolicy `json:"overwrite,omitempty"`
+}
+
+// RestoreLocation is where the data of a backup is stored
+type RestoreLocation struct {
+	Target BackupTarget `json:"target"`
+	// BackupName is the name of the MinioBackup which wrote the data
+	BackupName string `json:"backupName"`
+}
+
+// OverwritePolicy decides whether an object existing in the restored Minio is overwritten
+type OverwritePolicy string
+
+const (
+	// OverwriteNever keeps existing objects
+	OverwriteNever OverwritePolicy = "Never"
+	// OverwriteAlways replaces existing objects
+	OverwriteAlways OverwritePolicy = "Always"
+	// OverwriteIfChanged replaces existing objects whose ETag is different from the backed up object
+	OverwriteIfChanged OverwritePolicy = "IfChanged"
+)
+
+// RestorePhase is the phase of a restore
+type RestorePhase string
+
+const (
+	RestorePhasePending   RestorePhase = "Pending"
+	RestorePhaseRunning   RestorePhase = "Running"
+	RestorePhaseCompleted RestorePhase = "Completed"
+	RestorePhaseFailed    RestorePhase = "Failed"
+)
+
+// MinioRestoreStatus describes the progress and result of a restore
+type MinioRestoreStatus struct {
+	Phase          RestorePhase `json:"phase,omitempty"`
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Buckets is the buckets which are restored
+	Buckets []string `json:"buckets,omitempty"`
+	// CreatedBuckets is the buckets which did not exist and are created with the backed up settings
+	CreatedBuckets []string `json:"createdBuckets,omitempty"`
+	// ObjectsRestored is the number of objects written into the Minio
+	ObjectsRestored int64 `json:"objectsRestored,omitempty"`
+	// ObjectsSkipped is the number of existing objects kept by the overwrite policy
+	ObjectsSkipped int64 `json:"objectsSkipped,omitempty"`
+	BytesRestored  int64 `json:"bytesRestored,omitempty"`
+	ObjectsFailed  int64 `json:"objectsFailed,omitempty"`
+	// FailedKeys is the first failed objects in <bucket>/<key> format, it is truncated to 100 entries
+	FailedKeys []string `json:"failedKeys,omitempty"`
+	Message    string   `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MinioRestoreList carries a list of MinioRestore objects
+type MinioRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MinioRestore `json:"items"`
+}
@@ -14,6 +14,11 @@
 package v1alpha1
 
 import (
+	"strconv"
+
+	"github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -38,6 +43,251 @@ type MinioSpec struct {
 	Buckets    []string    `json:"buckets"`
 	Credential Credential  `json:"credential"`
 	Port       ServicePort `json:"port"`
+	// SiteReplication replicates buckets, objects and iam between this Minio and its peers
+	SiteReplication *SiteReplication `json:"siteReplication,omitempty"`
+	// Settings are the common server settings, changing them restarts members one by one
+	Settings *ServerSettings `json:"settings,omitempty"`
+	// Env is appended to the environment of minio containers, it overrides Settings. changing it restarts
+	// members one by one
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// EnvFrom is added to minio containers, members are restarted when the list is changed but not when
+	// the content of referenced objects is changed
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+	// Console deploys the minio console as its own deployment, it talks to minio through the internal service
+	Console *ConsoleSpec `json:"console,omitempty"`
+	// Identity configures external identity providers, changing it restarts members one by one
+	Identity *IdentitySpec `json:"identity,omitempty"`
+	// Encryption configures the KMS of minio and the default encryption of buckets
+	Encryption *EncryptionSpec `json:"encryption,omitempty"`
+	// Logging forwards audit events and server logs of minio
+	Logging *LoggingSpec `json:"logging,omitempty"`
+	// Monitoring generates the prometheus scrape configuration of minio
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+	// NetworkPolicy isolates minio from other pods of the cluster
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+}
+
+// NetworkPolicySpec describes who can reach minio, members of the same minio can always reach each other
+type NetworkPolicySpec struct {
+	// API are the peers allowed to reach the s3 port, only members, the console and the operator can reach it if empty
+	API []networkingv1.NetworkPolicyPeer `json:"api,omitempty"`
+	// Console are the peers allowed to reach the console port of minio and the console deployment, console is not
+	// restricted if empty
+	Console []networkingv1.NetworkPolicyPeer `json:"console,omitempty"`
+	// Operator are the peers of the operator, default is pods labelled app=clickpaas-operator-minio in all namespaces
+	Operator []networkingv1.NetworkPolicyPeer `json:"operator,omitempty"`
+}
+
+// MonitoringSpec describes how prometheus scrapes the cluster metrics of minio. the operator keeps a bearer token
+// and a scrape configuration in the secret <minio>-prometheus
+type MonitoringSpec struct {
+	// Public sets MINIO_PROMETHEUS_AUTH_TYPE=public so that metrics are scraped without token
+	Public bool `json:"public,omitempty"`
+	// ServiceMonitor creates a ServiceMonitor of prometheus operator, it is skipped if the crd is not installed
+	ServiceMonitor bool `json:"serviceMonitor,omitempty"`
+	// PodMonitor creates a PodMonitor of prometheus operator, it is skipped if the crd is not installed
+	PodMonitor bool `json:"podMonitor,omitempty"`
+	// Labels are added to the monitors so that prometheus selects them
+	Labels map[string]string `json:"labels,omitempty"`
+	// Interval of scraping, default is 30s
+	Interval string `json:"interval,omitempty"`
+}
+
+// LoggingSpec describes where minio sends its audit events and server logs
+type LoggingSpec struct {
+	// Audit targets receive audit events, MINIO_AUDIT_WEBHOOK_* and MINIO_AUDIT_KAFKA_*
+	Audit []LogTarget `json:"audit,omitempty"`
+	// Server targets receive server logs, MINIO_LOGGER_WEBHOOK_*, minio only supports webhook targets for them
+	Server []LogTarget `json:"server,omitempty"`
+	// Collector deploys a collector <minio>-log-collector which writes audit events to a pvc
+	Collector *LogCollector `json:"collector,omitempty"`
+}
+
+// LogTarget is a webhook or kafka target
+type LogTarget struct {
+	// Name identifies the target, it must be unique in audit or server
+	Name    string         `json:"name"`
+	Webhook *WebhookTarget `json:"webhook,omitempty"`
+	Kafka   *KafkaTarget   `json:"kafka,omitempty"`
+}
+
+// WebhookTarget posts logs to an http endpoint
+type WebhookTarget struct {
+	Endpoint string `json:"endpoint"`
+	// AuthToken is sent in the Authorization header
+	AuthToken *corev1.SecretKeySelector `json:"authToken,omitempty"`
+}
+
+// KafkaTarget publishes audit events to a kafka topic
+type KafkaTarget struct {
+	Brokers []string `json:"brokers"`
+	Topic   string   `json:"topic"`
+	TLS     bool     `json:"tls,omitempty"`
+	// SASLSecret holds username and password of sasl authentication, sasl is disabled if empty
+	SASLSecret string `json:"saslSecret,omitempty"`
+	// SASLMechanism is plain, sha256 or sha512, default is plain
+	SASLMechanism string `json:"saslMechanism,omitempty"`
+}
+
+// LogCollector receives audit events by webhook and appends them to files in a pvc
+type LogCollector struct {
+	Image string `json:"image,omitempty"`
+	// Size of the pvc, default is 10Gi
+	Size             string  `json:"size,omitempty"`
+	StorageClassName *string `json:"storageClassName,omitempty"`
+}
+
+const (
+	// BucketEncryptionSSES3 encrypts objects with keys derived from the default key of KMS
+	BucketEncryptionSSES3 = "SSE-S3"
+	// BucketEncryptionSSEKMS encrypts objects with the given key of KMS
+	BucketEncryptionSSEKMS = "SSE-KMS"
+)
+
+// EncryptionSpec connects minio to a KES server, it is deployed by operator unless endpoint is set
+type EncryptionSpec struct {
+	// KES is the KES server managed by operator, it is named <minio>-kes
+	KES *KESSpec `json:"kes,omitempty"`
+	// Endpoint is the url of an external KES server, e.g. https://kes.example.com:7373
+	Endpoint string `json:"endpoint,omitempty"`
+	// KeyName is the default key of KMS, MINIO_KMS_KES_KEY_NAME
+	KeyName string `json:"keyName"`
+	// ClientCertSecret is the tls secret (tls.crt and tls.key) minio authenticates to KES with
+	ClientCertSecret string `json:"clientCertSecret"`
+	// CASecret is the secret holding ca.crt which verifies the certificate of KES, system roots are used if empty
+	CASecret string `json:"caSecret,omitempty"`
+	// Buckets sets the default encryption of buckets
+	Buckets []BucketEncryption `json:"buckets,omitempty"`
+}
+
+// KESSpec describes the KES deployment managed by operator
+type KESSpec struct {
+	Image    string `json:"image,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+	// ServerCertSecret is the tls secret of KES, the certificate must be valid for <minio>-kes.<namespace>.svc
+	ServerCertSecret string `json:"serverCertSecret"`
+	// ConfigSecret holds server-config.yaml of KES, ${MINIO_KES_IDENTITY} in it is the identity of the
+	// client certificate of minio
+	ConfigSecret string `json:"configSecret"`
+}
+
+// BucketEncryption is the default encryption of a bucket
+type BucketEncryption struct {
+	Bucket string `json:"bucket"`
+	// Algorithm is SSE-S3 or SSE-KMS, default is SSE-KMS
+	Algorithm string `json:"algorithm,omitempty"`
+	// KeyID is the key of SSE-KMS, default is encryption.keyName
+	KeyID string `json:"keyID,omitempty"`
+}
+
+// IdentitySpec describes the identity providers of minio, they are rendered into server environment variables
+type IdentitySpec struct {
+	OpenID *OpenIDIdentity `json:"openid,omitempty"`
+	LDAP   *LDAPIdentity   `json:"ldap,omitempty"`
+}
+
+// OpenIDIdentity is an openid connect provider, MINIO_IDENTITY_OPENID_*
+type OpenIDIdentity struct {
+	// ConfigURL is the url of the openid configuration, e.g. https://idp.example.com/.well-known/openid-configuration
+	ConfigURL    string                   `json:"configURL"`
+	ClientID     string                   `json:"clientID"`
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	// ClaimName is the claim holding the policies of users, default is policy
+	ClaimName string   `json:"claimName,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	// RedirectURI is the callback url of the embedded console
+	RedirectURI string `json:"redirectURI,omitempty"`
+}
+
+// LDAPIdentity is an ldap or active directory server, MINIO_IDENTITY_LDAP_*
+type LDAPIdentity struct {
+	// ServerAddr is host:port of the ldap server
+	ServerAddr string `json:"serverAddr"`
+	// LookupBindSecret is the secret holding bindDN and bindPassword of the user which looks up users and groups
+	LookupBindSecret string `json:"lookupBindSecret"`
+	// UserDNSearchBaseDN is the base dn of user search
+	UserDNSearchBaseDN string `json:"userDNSearchBaseDN"`
+	// UserDNSearchFilter is the filter of user search, %s is replaced with the username, e.g. (uid=%s)
+	UserDNSearchFilter string `json:"userDNSearchFilter"`
+	GroupSearchBaseDN  string `json:"groupSearchBaseDN,omitempty"`
+	// GroupSearchFilter is the filter of group search, %d is replaced with the user dn, e.g. (&(objectclass=groupOfNames)(member=%d))
+	GroupSearchFilter string   `json:"groupSearchFilter,omitempty"`
+	TLS               *LDAPTLS `json:"tls,omitempty"`
+}
+
+// LDAPTLS describes how minio connects to the ldap server, default is ldaps with certificate verification
+type LDAPTLS struct {
+	// InsecureSkipVerify skips the verification of server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+	// StartTLS connects with plain ldap and upgrades to tls
+	StartTLS bool `json:"startTLS,omitempty"`
+	// Insecure connects with plain ldap without tls
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// ConsoleSpec describes the standalone minio console
+type ConsoleSpec struct {
+	Image    string `json:"image,omitempty"`
+	Replicas int32  `json:"replicas,omitempty"`
+	// Port is the port console listens on and the port of its service, default is 9090
+	Port int32 `json:"port,omitempty"`
+	// ServiceType is the type of the console service, default is ClusterIP
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty"`
+	// Ingress exposes the console service, no ingress is created if it is not set
+	Ingress *ConsoleIngress `json:"ingress,omitempty"`
+	// OIDC enables login with an openid provider
+	OIDC *ConsoleOIDC `json:"oidc,omitempty"`
+	// LDAP enables login with ldap users, ldap must be configured in minio
+	LDAP bool `json:"ldap,omitempty"`
+}
+
+// ConsoleIngress describes the ingress of console
+type ConsoleIngress struct {
+	Host             string `json:"host"`
+	IngressClassName string `json:"ingressClassName,omitempty"`
+	// TLSSecret is the secret holding the certificate of host, tls is disabled if it is empty
+	TLSSecret   string            `json:"tlsSecret,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ConsoleOIDC describes the openid provider of console
+type ConsoleOIDC struct {
+	// ConfigURL is the url of the openid configuration, e.g. https://idp.example.com/.well-known/openid-configuration
+	ConfigURL    string                   `json:"configURL"`
+	ClientID     string                   `json:"clientID"`
+	ClientSecret corev1.SecretKeySelector `json:"clientSecret"`
+	Scopes       []string                 `json:"scopes,omitempty"`
+	// CallbackURL is the url the provider redirects to after login, e.g. https://console.example.com/oauth_callback
+	CallbackURL string `json:"callbackURL,omitempty"`
+}
+
+// ServerSettings are the common settings of minio server, empty fields are left to the defaults of minio
+type ServerSettings struct {
+	// Browser enables the embedded console, MINIO_BROWSER
+	Browser *bool `json:"browser,omitempty"`
+	// Region is the region of the server, MINIO_REGION
+	Region string `json:"region,omitempty"`
+	// StorageClassStandard is the parity of the STANDARD storage class, e.g. EC:4, MINIO_STORAGE_CLASS_STANDARD
+	StorageClassStandard string `json:"storageClassStandard,omitempty"`
+	// StorageClassRRS is the parity of the REDUCED_REDUNDANCY storage class, e.g. EC:2, MINIO_STORAGE_CLASS_RRS
+	StorageClassRRS string `json:"storageClassRRS,omitempty"`
+	// PrometheusAuthType is jwt or public, MINIO_PROMETHEUS_AUTH_TYPE
+	PrometheusAuthType string `json:"prometheusAuthType,omitempty"`
+	// ScannerSpeed is one of fastest, fast, default, slow and slowest, MINIO_SCANNER_SPEED
+	ScannerSpeed string       `json:"scannerSpeed,omitempty"`
+	Compression  *Compression `json:"compression,omitempty"`
+}
+
+// Compression configures transparent compression of objects
+type Compression struct {
+	// Enabled is MINIO_COMPRESSION_ENABLE
+	Enabled bool `json:"enabled"`
+	// AllowEncryption allows compressing encrypted objects, MINIO_COMPRESSION_ALLOW_ENCRYPTION
+	AllowEncryption bool `json:"allowEncryption,omitempty"`
+	// Extensions of objects which are compressed, e.g. .txt, MINIO_COMPRESSION_EXTENSIONS
+	Extensions []string `json:"extensions,omitempty"`
+	// MimeTypes of objects which are compressed, e.g. text/*, MINIO_COMPRESSION_MIME_TYPES
+	MimeTypes []string `json:"mimeTypes,omitempty"`
 }
 
 type ServicePort struct {
@@ -52,11 +302,78 @@ type Credential struct {
 	SecretKey string `json:"secret_key"`
 }
 
+// SiteReplication describes the sites which are replicated with this Minio. all sites except one must be empty
+// when they are added, see the minio site replication document
+type SiteReplication struct {
+	// Name is the site name of this Minio, default is the name of the Minio
+	Name string `json:"name,omitempty"`
+	// Endpoint is the url which peers reach this Minio through, e.g. https://minio.dc1.example.com:9000. it is required
+	// by external peers, default is the address of the external service inside the kubernetes cluster
+	Endpoint string     `json:"endpoint,omitempty"`
+	Peers    []SitePeer `json:"peers"`
+	// Image is the mc image of the job which adds the sites
+	Image string `json:"image,omitempty"`
+}
+
+// SitePeer is a peer site, it is either a Minio managed by the operator or an external minio
+type SitePeer struct {
+	// Name is the site name of the peer, default is the name of the referenced Minio. it is required by external peers
+	Name string `json:"name,omitempty"`
+	// Minio references a Minio in the same kubernetes cluster
+	Minio *MinioReference `json:"minio,omitempty"`
+	// Endpoint is the url of an external minio, e.g. https://minio.example.com:9000
+	Endpoint string `json:"endpoint,omitempty"`
+	// CredentialSecret is the secret holding accessKey/secretKey of the root user of the external minio
+	CredentialSecret string `json:"credentialSecret,omitempty"`
+}
+
+// MinioReference references a Minio object, namespace defaults to the namespace of the referrer
+type MinioReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// condition types of Minio
+const (
+	// MinioConditionSiteReplicationHealthy is true if all sites are replicated and online
+	MinioConditionSiteReplicationHealthy = "SiteReplicationHealthy"
+	// MinioConditionEncryptionReady is true if the keys of KMS are available and buckets are encrypted as requested
+	MinioConditionEncryptionReady = "EncryptionReady"
+	// MinioConditionPaused is true if the reconciliation is paused by PausedAnnotation
+	MinioConditionPaused = "Paused"
+)
+
+// PausedAnnotation pauses the reconciliation of a minio if its value is true, the status is still updated
+const PausedAnnotation = miniooperator.GroupName + "/paused"
+
+// IsMinioPaused returns true if the reconciliation of minio is paused by PausedAnnotation
+func IsMinioPaused(minio *Minio) bool {
+	paused, _ := strconv.ParseBool(minio.GetAnnotations()[PausedAnnotation])
+	return paused
+}
+
 // MinioStatus describes the current status of Minio applications
 type MinioStatus struct {
 	Inited string `json:"inited"`
+	// CredentialHash is the hash of the root credential accepted by the running cluster
+	CredentialHash string `json:"credentialHash,omitempty"`
+	// CredentialRotationTime is the last time the root credential was rotated
+	CredentialRotationTime *metav1.Time       `json:"credentialRotationTime,omitempty"`
+	Conditions             []metav1.Condition `json:"conditions,omitempty"`
+	// ReadyReplicas is the number of ready members, it is the status replicas of the scale subresource
+	ReadyReplicas int32 `json:"readyReplicas"`
+	// Phase is Running if all members are ready, otherwise Degraded
+	Phase string `json:"phase,omitempty"`
+	// Selector is the label selector of members, it is the selector of the scale subresource
+	Selector string `json:"selector,omitempty"`
 }
 
+// phases of Minio
+const (
+	MinioPhaseRunning  = "Running"
+	MinioPhaseDegraded = "Degraded"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // MinioList carries a list of Minio objects
@@ -11,5 +11,8 @@
    limitations under the License.
 */
 
-package minio
+// +k8s:deepcopy-gen=package
+// +groupName=miniooperator.3xpl0it3r.cn
 
+// Package v1beta1 is the v1beta1 version of the API.
+package v1beta1 // import "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
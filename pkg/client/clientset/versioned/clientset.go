@@ -23,6 +23,7 @@ import (
 	"net/http"
 
 	miniooperatorv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1alpha1"
+	miniooperatorv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1beta1"
 	discovery "k8s.io/client-go/discovery"
 	rest "k8s.io/client-go/rest"
 	flowcontrol "k8s.io/client-go/util/flowcontrol"
@@ -31,6 +32,7 @@ import (
 type Interface interface {
 	Discovery() discovery.DiscoveryInterface
 	MiniooperatorV1alpha1() miniooperatorv1alpha1.MiniooperatorV1alpha1Interface
+	MiniooperatorV1beta1() miniooperatorv1beta1.MiniooperatorV1beta1Interface
 }
 
 // Clientset contains the clients for groups. Each group has exactly one
@@ -38,6 +40,7 @@ type Interface interface {
 type Clientset struct {
 	*discovery.DiscoveryClient
 	miniooperatorV1alpha1 *miniooperatorv1alpha1.MiniooperatorV1alpha1Client
+	miniooperatorV1beta1  *miniooperatorv1beta1.MiniooperatorV1beta1Client
 }
 
 // MiniooperatorV1alpha1 retrieves the MiniooperatorV1alpha1Client
@@ -45,6 +48,11 @@ func (c *Clientset) MiniooperatorV1alpha1() miniooperatorv1alpha1.MiniooperatorV
 	return c.miniooperatorV1alpha1
 }
 
+// MiniooperatorV1beta1 retrieves the MiniooperatorV1beta1Client
+func (c *Clientset) MiniooperatorV1beta1() miniooperatorv1beta1.MiniooperatorV1beta1Interface {
+	return c.miniooperatorV1beta1
+}
+
 // Discovery retrieves the DiscoveryClient
 func (c *Clientset) Discovery() discovery.DiscoveryInterface {
 	if c == nil {
@@ -93,6 +101,10 @@ func NewForConfigAndClient(c *rest.Config, httpClient *http.Client) (*Clientset,
 	if err != nil {
 		return nil, err
 	}
+	cs.miniooperatorV1beta1, err = miniooperatorv1beta1.NewForConfigAndClient(&configShallowCopy, httpClient)
+	if err != nil {
+		return nil, err
+	}
 
 	cs.DiscoveryClient, err = discovery.NewDiscoveryClientForConfigAndClient(&configShallowCopy, httpClient)
 	if err != nil {
@@ -115,6 +127,7 @@ func NewForConfigOrDie(c *rest.Config) *Clientset {
 func New(c rest.Interface) *Clientset {
 	var cs Clientset
 	cs.miniooperatorV1alpha1 = miniooperatorv1alpha1.New(c)
+	cs.miniooperatorV1beta1 = miniooperatorv1beta1.New(c)
 
 	cs.DiscoveryClient = discovery.NewDiscoveryClient(c)
 	return &cs
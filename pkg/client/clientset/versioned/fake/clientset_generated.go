@@ -22,6 +22,8 @@ import (
 	clientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
 	miniooperatorv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1alpha1"
 	fakeminiooperatorv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1alpha1/fake"
+	miniooperatorv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1beta1"
+	fakeminiooperatorv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned/typed/miniooperator.3xpl0it3r.cn/v1beta1/fake"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/discovery"
@@ -83,3 +85,8 @@ var (
 func (c *Clientset) MiniooperatorV1alpha1() miniooperatorv1alpha1.MiniooperatorV1alpha1Interface {
 	return &fakeminiooperatorv1alpha1.FakeMiniooperatorV1alpha1{Fake: &c.Fake}
 }
+
+// MiniooperatorV1beta1 retrieves the MiniooperatorV1beta1Client
+func (c *Clientset) MiniooperatorV1beta1() miniooperatorv1beta1.MiniooperatorV1beta1Interface {
+	return &fakeminiooperatorv1beta1.FakeMiniooperatorV1beta1{Fake: &c.Fake}
+}
@@ -32,6 +32,18 @@ func (c *FakeMiniooperatorV1alpha1) Minios(namespace string) v1alpha1.MinioInter
 	return &FakeMinios{c, namespace}
 }
 
+func (c *FakeMiniooperatorV1alpha1) MinioBackups(namespace string) v1alpha1.MinioBackupInterface {
+	return &FakeMinioBackups{c, namespace}
+}
+
+func (c *FakeMiniooperatorV1alpha1) MinioBackupSchedules(namespace string) v1alpha1.MinioBackupScheduleInterface {
+	return &FakeMinioBackupSchedules{c, namespace}
+}
+
+func (c *FakeMiniooperatorV1alpha1) MinioRestores(namespace string) v1alpha1.MinioRestoreInterface {
+	return &FakeMinioRestores{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeMiniooperatorV1alpha1) RESTClient() rest.Interface {
@@ -19,3 +19,9 @@ limitations under the License.
 package v1alpha1
 
 type MinioExpansion interface{}
+
+type MinioBackupExpansion interface{}
+
+type MinioBackupScheduleExpansion interface{}
+
+type MinioRestoreExpansion interface{}
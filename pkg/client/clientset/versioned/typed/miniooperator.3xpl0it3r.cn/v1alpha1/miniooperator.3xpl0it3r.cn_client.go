@@ -29,6 +29,9 @@ import (
 type MiniooperatorV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	MiniosGetter
+	MinioBackupsGetter
+	MinioBackupSchedulesGetter
+	MinioRestoresGetter
 }
 
 // MiniooperatorV1alpha1Client is used to interact with features provided by the miniooperator.3xpl0it3r.cn group.
@@ -40,6 +43,18 @@ func (c *MiniooperatorV1alpha1Client) Minios(namespace string) MinioInterface {
 	return newMinios(c, namespace)
 }
 
+func (c *MiniooperatorV1alpha1Client) MinioBackups(namespace string) MinioBackupInterface {
+	return newMinioBackups(c, namespace)
+}
+
+func (c *MiniooperatorV1alpha1Client) MinioBackupSchedules(namespace string) MinioBackupScheduleInterface {
+	return newMinioBackupSchedules(c, namespace)
+}
+
+func (c *MiniooperatorV1alpha1Client) MinioRestores(namespace string) MinioRestoreInterface {
+	return newMinioRestores(c, namespace)
+}
+
 // NewForConfig creates a new MiniooperatorV1alpha1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -22,6 +22,7 @@ import (
 	"fmt"
 
 	v1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	v1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 	cache "k8s.io/client-go/tools/cache"
 )
@@ -55,6 +56,16 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 	// Group=miniooperator.3xpl0it3r.cn, Version=v1alpha1
 	case v1alpha1.SchemeGroupVersion.WithResource("minios"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Miniooperator().V1alpha1().Minios().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("miniobackups"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Miniooperator().V1alpha1().MinioBackups().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("miniobackupschedules"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Miniooperator().V1alpha1().MinioBackupSchedules().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("miniorestores"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Miniooperator().V1alpha1().MinioRestores().Informer()}, nil
+
+		// Group=miniooperator.3xpl0it3r.cn, Version=v1beta1
+	case v1beta1.SchemeGroupVersion.WithResource("minios"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Miniooperator().V1beta1().Minios().Informer()}, nil
 
 	}
 
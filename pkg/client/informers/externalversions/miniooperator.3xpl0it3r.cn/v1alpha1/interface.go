@@ -26,6 +26,12 @@ import (
 type Interface interface {
 	// Minios returns a MinioInformer.
 	Minios() MinioInformer
+	// MinioBackups returns a MinioBackupInformer.
+	MinioBackups() MinioBackupInformer
+	// MinioBackupSchedules returns a MinioBackupScheduleInformer.
+	MinioBackupSchedules() MinioBackupScheduleInformer
+	// MinioRestores returns a MinioRestoreInformer.
+	MinioRestores() MinioRestoreInformer
 }
 
 type version struct {
@@ -43,3 +49,18 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 func (v *version) Minios() MinioInformer {
 	return &minioInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// MinioBackups returns a MinioBackupInformer.
+func (v *version) MinioBackups() MinioBackupInformer {
+	return &minioBackupInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// MinioBackupSchedules returns a MinioBackupScheduleInformer.
+func (v *version) MinioBackupSchedules() MinioBackupScheduleInformer {
+	return &minioBackupScheduleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// MinioRestores returns a MinioRestoreInformer.
+func (v *version) MinioRestores() MinioRestoreInformer {
+	return &minioRestoreInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
@@ -25,3 +25,27 @@ type MinioListerExpansion interface{}
 // MinioNamespaceListerExpansion allows custom methods to be added to
 // MinioNamespaceLister.
 type MinioNamespaceListerExpansion interface{}
+
+// MinioBackupListerExpansion allows custom methods to be added to
+// MinioBackupLister.
+type MinioBackupListerExpansion interface{}
+
+// MinioBackupNamespaceListerExpansion allows custom methods to be added to
+// MinioBackupNamespaceLister.
+type MinioBackupNamespaceListerExpansion interface{}
+
+// MinioBackupScheduleListerExpansion allows custom methods to be added to
+// MinioBackupScheduleLister.
+type MinioBackupScheduleListerExpansion interface{}
+
+// MinioBackupScheduleNamespaceListerExpansion allows custom methods to be added to
+// MinioBackupScheduleNamespaceLister.
+type MinioBackupScheduleNamespaceListerExpansion interface{}
+
+// MinioRestoreListerExpansion allows custom methods to be added to
+// MinioRestoreLister.
+type MinioRestoreListerExpansion interface{}
+
+// MinioRestoreNamespaceListerExpansion allows custom methods to be added to
+// MinioRestoreNamespaceLister.
+type MinioRestoreNamespaceListerExpansion interface{}
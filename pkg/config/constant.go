@@ -10,4 +10,28 @@ const (
 	MinioAppNameLabel          = MinioLabelAnnotationPrefix + "app-name"
 
     MinioAppLocation = MinioLabelAnnotationPrefix + "nodeName"
+	// MinioCredentialHash is the hash of root credential which the pod is created with
+	MinioCredentialHash = MinioLabelAnnotationPrefix + "credential-hash"
+	// MinioConsoleLabel selects the console pods of a minio, minio pods do not have it
+	MinioConsoleLabel = MinioLabelAnnotationPrefix + "console"
+	// MinioKESLabel selects the KES pods of a minio
+	MinioKESLabel = MinioLabelAnnotationPrefix + "kes"
+	// MinioSiteReplicationLabel selects the pods of the site replication job of a minio
+	MinioSiteReplicationLabel = MinioLabelAnnotationPrefix + "site-replication"
+	// MinioLogCollectorLabel selects the log collector pods of a minio
+	MinioLogCollectorLabel = MinioLabelAnnotationPrefix + "log-collector"
+	// MinioInternalServiceLabel marks the internal service of a minio
+	MinioInternalServiceLabel = MinioLabelAnnotationPrefix + "internal-service"
+	// MinioSpecHash is the hash of the desired spec of an object which the operator updates when it is changed
+	MinioSpecHash = MinioLabelAnnotationPrefix + "spec-hash"
+	// MinioPorts is the s3 and console ports which the pod listens on
+	MinioPorts = MinioLabelAnnotationPrefix + "ports"
+	// MinioSettingsHash is the hash of server settings which the pod is created with
+	MinioSettingsHash = MinioLabelAnnotationPrefix + "settings-hash"
+	// MinioBackupScheduleLabel is the name of the schedule which created the backup
+	MinioBackupScheduleLabel = MinioLabelAnnotationPrefix + "backup-schedule"
+	// MinioBackupFinalizer makes sure the backup data in target is deleted with the backup
+	MinioBackupFinalizer = MinioLabelAnnotationPrefix + "backup-cleanup"
+	// MinioSiteReplicationHash is the hash of the sites which the site replication job adds
+	MinioSiteReplicationHash = MinioLabelAnnotationPrefix + "site-replication-hash"
 )
@@ -13,21 +13,33 @@
 
 package controller
 
-import "errors"
+import (
+	"errors"
+	"sync"
+)
 
+// Base keeps the hooks of controller, it is safe for concurrent use
 type Base struct {
-	hooks []Hook
+	hooksLock sync.RWMutex
+	hooks     []Hook
 }
 
 func NewControllerBase() Base {
 	return Base{hooks: []Hook{}}
 }
 
+// GetHooks return a snapshot of installed hooks
 func (c *Base) GetHooks() []Hook {
-	return c.hooks
+	c.hooksLock.RLock()
+	defer c.hooksLock.RUnlock()
+	hooks := make([]Hook, len(c.hooks))
+	copy(hooks, c.hooks)
+	return hooks
 }
 
 func (c *Base) AddHook(hook Hook) error {
+	c.hooksLock.Lock()
+	defer c.hooksLock.Unlock()
 	for _, h := range c.hooks {
 		if h == hook {
 			return errors.New("Given hook is already installed in the current controller ")
@@ -38,9 +50,14 @@ func (c *Base) AddHook(hook Hook) error {
 }
 
 func (c *Base) RemoveHook(hook Hook) error {
+	c.hooksLock.Lock()
+	defer c.hooksLock.Unlock()
 	for i, h := range c.hooks {
 		if h == hook {
-			c.hooks = append(c.hooks[:i], c.hooks[i+1:]...)
+			// do not modify the backing array in place, snapshots returned by GetHooks may still use it
+			hooks := make([]Hook, 0, len(c.hooks)-1)
+			hooks = append(hooks, c.hooks[:i]...)
+			c.hooks = append(hooks, c.hooks[i+1:]...)
 			return nil
 		}
 	}
@@ -14,6 +14,8 @@
 package controller
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -23,6 +25,10 @@ type Controller interface {
 	Stop()
 	AddHook(hook Hook) error
 	RemoveHook(hook Hook) error
+	// Ready return nil once all informers of the controller have synced and workers are started
+	Ready() error
+	// Live return error if the controller has pending work but has not made progress for longer than stallTimeout
+	Live(stallTimeout time.Duration) error
 }
 
 // this is example, you should remove it in product
@@ -40,6 +46,12 @@ func (e emptyController) AddHook(hook Hook) error {
 func (e emptyController) RemoveHook(hook Hook) error {
 	return nil
 }
+func (e emptyController) Ready() error {
+	return nil
+}
+func (e emptyController) Live(time.Duration) error {
+	return nil
+}
 func NewEmptyController(reg prometheus.Registerer) Controller {
 	return &emptyController{}
 }
@@ -13,6 +13,8 @@
 
 package controller
 
+import "sync/atomic"
+
 // EventType represents the type of a Event
 type EventType int
 
@@ -33,33 +35,49 @@ type Event struct {
 type EventsHook interface {
 	Hook
 	GetEventsChan() <-chan Event
+	// Dropped return the number of events dropped because the channel was full
+	Dropped() uint64
 }
 
 type eventsHooks struct {
-	events chan Event
+	events  chan Event
+	dropped uint64
 }
 
 func (e *eventsHooks) OnAdd(object interface{}) {
-	e.events <- Event{
+	e.send(Event{
 		Type:   EventAdded,
 		Object: object,
-	}
+	})
 }
 
 func (e *eventsHooks) OnUpdate(object interface{}) {
-	e.events <- Event{
+	e.send(Event{
 		Type:   EventUpdated,
 		Object: object,
-	}
+	})
 }
 
 func (e *eventsHooks) OnDelete(object interface{}) {
-	e.events <- Event{
+	e.send(Event{
 		Type:   EventDeleted,
 		Object: object,
+	})
+}
+
+// send never blocks the controller, the event is dropped if nobody consumes the channel
+func (e *eventsHooks) send(event Event) {
+	select {
+	case e.events <- event:
+	default:
+		atomic.AddUint64(&e.dropped, 1)
 	}
 }
 
+func (e *eventsHooks) Dropped() uint64 {
+	return atomic.LoadUint64(&e.dropped)
+}
+
 func (e *eventsHooks) GetEventsChan() <-chan Event {
 	return e.events
 }
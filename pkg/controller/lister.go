@@ -0,0 +1,177 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package controller
+
+import (
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	listercorev1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crlisterv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/listers/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+// controllers run one informer per watched namespace, the listers below dispatch every lookup
+// to the lister of the informer which watches the given namespace. the key apicorev1.NamespaceAll
+// means the informer watches all namespaces.
+
+// emptyIndexer is used for namespaces which are not watched, every Get on it returns NotFound
+func emptyIndexer() cache.Indexer {
+	return cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+}
+
+// NamespacedMinioLister implement crlisterv1alpha1.MinioLister for multi namespaces
+type NamespacedMinioLister map[string]crlisterv1alpha1.MinioLister
+
+func (l NamespacedMinioLister) List(selector labels.Selector) (ret []*crapiv1alpha1.Minio, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedMinioLister) Minios(namespace string) crlisterv1alpha1.MinioNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.Minios(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.Minios(namespace)
+	}
+	return crlisterv1alpha1.NewMinioLister(emptyIndexer()).Minios(namespace)
+}
+
+// NamespacedPodLister implement listercorev1.PodLister for multi namespaces
+type NamespacedPodLister map[string]listercorev1.PodLister
+
+func (l NamespacedPodLister) List(selector labels.Selector) (ret []*apicorev1.Pod, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedPodLister) Pods(namespace string) listercorev1.PodNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.Pods(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.Pods(namespace)
+	}
+	return listercorev1.NewPodLister(emptyIndexer()).Pods(namespace)
+}
+
+// NamespacedServiceLister implement listercorev1.ServiceLister for multi namespaces
+type NamespacedServiceLister map[string]listercorev1.ServiceLister
+
+func (l NamespacedServiceLister) List(selector labels.Selector) (ret []*apicorev1.Service, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedServiceLister) Services(namespace string) listercorev1.ServiceNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.Services(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.Services(namespace)
+	}
+	return listercorev1.NewServiceLister(emptyIndexer()).Services(namespace)
+}
+
+// NamespacedMinioBackupLister implement crlisterv1alpha1.MinioBackupLister for multi namespaces
+type NamespacedMinioBackupLister map[string]crlisterv1alpha1.MinioBackupLister
+
+func (l NamespacedMinioBackupLister) List(selector labels.Selector) (ret []*crapiv1alpha1.MinioBackup, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedMinioBackupLister) MinioBackups(namespace string) crlisterv1alpha1.MinioBackupNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.MinioBackups(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.MinioBackups(namespace)
+	}
+	return crlisterv1alpha1.NewMinioBackupLister(emptyIndexer()).MinioBackups(namespace)
+}
+
+// NamespacedMinioBackupScheduleLister implement crlisterv1alpha1.MinioBackupScheduleLister for multi namespaces
+type NamespacedMinioBackupScheduleLister map[string]crlisterv1alpha1.MinioBackupScheduleLister
+
+func (l NamespacedMinioBackupScheduleLister) List(selector labels.Selector) (ret []*crapiv1alpha1.MinioBackupSchedule, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedMinioBackupScheduleLister) MinioBackupSchedules(namespace string) crlisterv1alpha1.MinioBackupScheduleNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.MinioBackupSchedules(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.MinioBackupSchedules(namespace)
+	}
+	return crlisterv1alpha1.NewMinioBackupScheduleLister(emptyIndexer()).MinioBackupSchedules(namespace)
+}
+
+// NamespacedMinioRestoreLister implement crlisterv1alpha1.MinioRestoreLister for multi namespaces
+type NamespacedMinioRestoreLister map[string]crlisterv1alpha1.MinioRestoreLister
+
+func (l NamespacedMinioRestoreLister) List(selector labels.Selector) (ret []*crapiv1alpha1.MinioRestore, err error) {
+	for _, lister := range l {
+		items, err := lister.List(selector)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, items...)
+	}
+	return ret, nil
+}
+
+func (l NamespacedMinioRestoreLister) MinioRestores(namespace string) crlisterv1alpha1.MinioRestoreNamespaceLister {
+	if lister, ok := l[namespace]; ok {
+		return lister.MinioRestores(namespace)
+	}
+	if lister, ok := l[apicorev1.NamespaceAll]; ok {
+		return lister.MinioRestores(namespace)
+	}
+	return crlisterv1alpha1.NewMinioRestoreLister(emptyIndexer()).MinioRestores(namespace)
+}
@@ -11,3 +11,133 @@
    limitations under the License.
 */
 package minio
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	metricsNamespace = "minio_operator"
+	// workqueueName is the name of minio workqueue, it is used as the label of workqueue metrics
+	workqueueName = "minio"
+)
+
+// workqueueMetricsProvider implement workqueue.MetricsProvider, it exposes workqueue metrics to prometheus
+type workqueueMetricsProvider struct {
+	depth                   *prometheus.GaugeVec
+	adds                    *prometheus.CounterVec
+	latency                 *prometheus.HistogramVec
+	workDuration            *prometheus.HistogramVec
+	unfinishedWork          *prometheus.GaugeVec
+	longestRunningProcessor *prometheus.GaugeVec
+	retries                 *prometheus.CounterVec
+}
+
+func newWorkqueueMetricsProvider() *workqueueMetricsProvider {
+	return &workqueueMetricsProvider{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "depth",
+			Help: "Current depth of workqueue",
+		}, []string{"name"}),
+		adds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "adds_total",
+			Help: "Total number of adds handled by workqueue",
+		}, []string{"name"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "queue_duration_seconds",
+			Help:    "How long in seconds an item stays in workqueue before being requested",
+			Buckets: prometheus.ExponentialBuckets(10e-9, 10, 10),
+		}, []string{"name"}),
+		workDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "work_duration_seconds",
+			Help:    "How long in seconds processing an item from workqueue takes",
+			Buckets: prometheus.ExponentialBuckets(10e-9, 10, 10),
+		}, []string{"name"}),
+		unfinishedWork: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "unfinished_work_seconds",
+			Help: "How many seconds of work has been done that is in progress and hasn't been observed by work_duration",
+		}, []string{"name"}),
+		longestRunningProcessor: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "longest_running_processor_seconds",
+			Help: "How many seconds has the longest running processor for workqueue been running",
+		}, []string{"name"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Subsystem: "workqueue", Name: "retries_total",
+			Help: "Total number of retries handled by workqueue",
+		}, []string{"name"}),
+	}
+}
+
+func (p *workqueueMetricsProvider) collectors() []prometheus.Collector {
+	return []prometheus.Collector{p.depth, p.adds, p.latency, p.workDuration, p.unfinishedWork, p.longestRunningProcessor, p.retries}
+}
+
+func (p *workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return p.depth.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return p.adds.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return p.latency.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return p.workDuration.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.unfinishedWork.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return p.longestRunningProcessor.WithLabelValues(name)
+}
+
+func (p *workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return p.retries.WithLabelValues(name)
+}
+
+// controllerMetrics represent the metrics of reconcile loop
+type controllerMetrics struct {
+	reconcileDuration *prometheus.HistogramVec
+	reconcileErrors   *prometheus.CounterVec
+}
+
+func newControllerMetrics() *controllerMetrics {
+	return &controllerMetrics{
+		reconcileDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace, Name: "reconcile_duration_seconds",
+			Help:    "How long in seconds reconciling a minio takes",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+		}, []string{"namespace", "name"}),
+		reconcileErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace, Name: "reconcile_errors_total",
+			Help: "Total number of failed reconciles of a minio",
+		}, []string{"namespace", "name"}),
+	}
+}
+
+func (m *controllerMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.reconcileDuration, m.reconcileErrors}
+}
+
+// forget remove all series of the given minio
+func (m *controllerMetrics) forget(namespace, name string) {
+	m.reconcileDuration.DeleteLabelValues(namespace, name)
+	m.reconcileErrors.DeleteLabelValues(namespace, name)
+}
+
+// registerMetrics register workqueue and controller metrics to reg, it must be called before the workqueue is created
+func registerMetrics(reg prometheus.Registerer, metrics *controllerMetrics) {
+	if reg == nil {
+		return
+	}
+	provider := newWorkqueueMetricsProvider()
+	reg.MustRegister(provider.collectors()...)
+	reg.MustRegister(metrics.collectors()...)
+	workqueue.SetProvider(provider)
+}
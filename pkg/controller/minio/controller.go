@@ -14,16 +14,17 @@ package minio
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	kubeclientset "k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/kubernetes/scheme"
-	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	listercorev1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -45,9 +46,11 @@ type controller struct {
 	register      prometheus.Registerer
 	kubeClientSet kubeclientset.Interface
 	crClientSet   crclientset.Interface
+	dynamicClient dynamic.Interface
 	queue         workqueue.RateLimitingInterface
 	operator      croperator.Operator
 	recorder      record.EventRecorder
+	metrics       *controllerMetrics
 
 	minioLister   crlisterv1alpha1.MinioLister
 	serviceLister listercorev1.ServiceLister
@@ -55,51 +58,75 @@ type controller struct {
 	nodeLister    listercorev1.NodeLister
 
 	cacheSynced []cache.InformerSynced
-}
 
-// NewController create a new controller for Minio resources
-func NewController(kubeClientSet kubeclientset.Interface, kubeInformers informers.SharedInformerFactory, crClientSet crclientset.Interface,
-	crInformers crinformers.SharedInformerFactory, reg prometheus.Registerer) crcontroller.Controller {
-	eventBroadcaster := record.NewBroadcaster()
-	eventBroadcaster.StartLogging(klog.V(2).Infof)
-	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientSet.CoreV1().Events(apicorev1.NamespaceAll)})
-	recorder := eventBroadcaster.NewRecorder(scheme.Scheme, apicorev1.EventSource{Component: "Minio-operator"})
+	// synced is set to 1 after all caches have synced
+	synced int32
+	// processing is the number of items being processed by workers
+	processing int32
+	// lastProgress is the unix nano time when a worker finished an item last time
+	lastProgress int64
+	// processed keeps the last reconciled object of every key, it is used to tell add from update for hooks
+	processed sync.Map
+}
 
-	return newMinioController(kubeClientSet, kubeInformers, crClientSet, crInformers, recorder, reg)
+// NewController create a new controller for Minio resources, clusterInformers is used for cluster scoped resources(nodes),
+// kubeInformers and crInformers are keyed by the namespace they watch
+func NewController(kubeClientSet kubeclientset.Interface, clusterInformers informers.SharedInformerFactory, kubeInformers map[string]informers.SharedInformerFactory,
+	crClientSet crclientset.Interface, crInformers map[string]crinformers.SharedInformerFactory, dynamicClient dynamic.Interface, resyncPeriod time.Duration, reg prometheus.Registerer) crcontroller.Controller {
+	recorder := crcontroller.NewEventRecorder(kubeClientSet)
+	return newMinioController(kubeClientSet, clusterInformers, kubeInformers, crClientSet, crInformers, dynamicClient, resyncPeriod, recorder, reg)
 }
 
 // newMinioController is really
-func newMinioController(kubeClientSet kubeclientset.Interface, kubeInformers informers.SharedInformerFactory, crClientSet crclientset.Interface,
-	crInformers crinformers.SharedInformerFactory, recorder record.EventRecorder, reg prometheus.Registerer) *controller {
+func newMinioController(kubeClientSet kubeclientset.Interface, clusterInformers informers.SharedInformerFactory, kubeInformers map[string]informers.SharedInformerFactory,
+	crClientSet crclientset.Interface, crInformers map[string]crinformers.SharedInformerFactory, dynamicClient dynamic.Interface, resyncPeriod time.Duration, recorder record.EventRecorder, reg prometheus.Registerer) *controller {
 	c := &controller{
 		register:      reg,
 		kubeClientSet: kubeClientSet,
 		crClientSet:   crClientSet,
+		dynamicClient: dynamicClient,
 		recorder:      recorder,
+		metrics:       newControllerMetrics(),
+	}
+	registerMetrics(reg, c.metrics)
+	c.queue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), workqueueName)
+
+	// listers must be ready before event handlers are registered, for handlers use them to find the owner minio
+	minioListers := crcontroller.NamespacedMinioLister{}
+	for namespace, factory := range crInformers {
+		minioListers[namespace] = factory.Miniooperator().V1alpha1().Minios().Lister()
+	}
+	podListers := crcontroller.NamespacedPodLister{}
+	serviceListers := crcontroller.NamespacedServiceLister{}
+	for namespace, factory := range kubeInformers {
+		podListers[namespace] = factory.Core().V1().Pods().Lister()
+		serviceListers[namespace] = factory.Core().V1().Services().Lister()
 	}
-	c.queue = workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	c.minioLister = minioListers
+	c.podLister = podListers
+	c.serviceLister = serviceListers
 
-	minioInformer := crInformers.Miniooperator().V1alpha1().Minios()
-	minioInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewMinioEventHandler(c.enqueueFunc, c.minioLister), 5*time.Second)
-	c.minioLister = minioInformer.Lister()
-	c.cacheSynced = append(c.cacheSynced, minioInformer.Informer().HasSynced)
+	for _, factory := range crInformers {
+		minioInformer := factory.Miniooperator().V1alpha1().Minios()
+		minioInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewMinioEventHandler(c.enqueueFunc, c.minioLister), resyncPeriod)
+		c.cacheSynced = append(c.cacheSynced, minioInformer.Informer().HasSynced)
+	}
 
-	// add
-	podInformer := kubeInformers.Core().V1().Pods()
-	podInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewPodEventHandler(c.enqueueFunc, c.podLister, c.minioLister), 5*time.Second)
-	c.podLister = podInformer.Lister()
-	c.cacheSynced = append(c.cacheSynced, podInformer.Informer().HasSynced)
+	for _, factory := range kubeInformers {
+		podInformer := factory.Core().V1().Pods()
+		podInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewPodEventHandler(c.enqueueFunc, c.podLister, c.minioLister), resyncPeriod)
+		c.cacheSynced = append(c.cacheSynced, podInformer.Informer().HasSynced)
 
-	serviceInformer := kubeInformers.Core().V1().Services()
-	serviceInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewServiceEventHandler(c.serviceLister, c.enqueueFunc, c.minioLister), 5*time.Second)
-	c.serviceLister = serviceInformer.Lister()
-	c.cacheSynced = append(c.cacheSynced, serviceInformer.Informer().HasSynced)
+		serviceInformer := factory.Core().V1().Services()
+		serviceInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewServiceEventHandler(c.serviceLister, c.enqueueFunc, c.minioLister), resyncPeriod)
+		c.cacheSynced = append(c.cacheSynced, serviceInformer.Informer().HasSynced)
+	}
 
-	nodeInformer := kubeInformers.Core().V1().Nodes()
+	nodeInformer := clusterInformers.Core().V1().Nodes()
 	c.nodeLister = nodeInformer.Lister()
 	c.cacheSynced = append(c.cacheSynced, nodeInformer.Informer().HasSynced)
 
-	c.operator = miniooperator.NewOperator(c.kubeClientSet, c.crClientSet, c.podLister, c.serviceLister, c.nodeLister, c.minioLister, c.recorder, c.register)
+	c.operator = miniooperator.NewOperator(c.kubeClientSet, c.crClientSet, c.dynamicClient, c.podLister, c.serviceLister, c.nodeLister, c.minioLister, c.recorder, c.register)
 	return c
 }
 
@@ -116,6 +143,8 @@ func (c *controller) Start(worker int, stopCh <-chan struct{}) error {
 		return fmt.Errorf("timeout wait for cache to be synced")
 	}
 	klog.Infof("All Informer has all synced, Controller Begin to start worker")
+	c.markProgress()
+	atomic.StoreInt32(&c.synced, 1)
 	for i := 0; i < worker; i++ {
 		go wait.Until(c.runWorker, time.Second, stopCh)
 	}
@@ -136,17 +165,110 @@ func (c *controller) processNextItem() bool {
 	if shutdown {
 		return false
 	}
+	atomic.AddInt32(&c.processing, 1)
 	defer func() {
 		c.queue.Done(obj)
+		c.markProgress()
+		atomic.AddInt32(&c.processing, -1)
 	}()
-	if err := c.operator.Reconcile(obj); err != nil {
+	startTime := time.Now()
+	err := c.operator.Reconcile(obj)
+	c.observeReconcile(obj, time.Since(startTime), err)
+	if err != nil {
 		c.queue.AddRateLimited(obj)
 		utilruntime.HandleError(err)
+	} else {
+		c.runHooks(obj)
 	}
 	c.queue.Forget(obj)
 	return true
 }
 
+// observeReconcile record reconcile metrics of the given key, series of deleted minio are removed
+func (c *controller) observeReconcile(obj interface{}, duration time.Duration, err error) {
+	key, ok := obj.(string)
+	if !ok {
+		return
+	}
+	namespace, name, splitErr := cache.SplitMetaNamespaceKey(key)
+	if splitErr != nil {
+		return
+	}
+	if _, getErr := c.minioLister.Minios(namespace).Get(name); k8serror.IsNotFound(getErr) {
+		c.metrics.forget(namespace, name)
+		return
+	}
+	c.metrics.reconcileDuration.WithLabelValues(namespace, name).Observe(duration.Seconds())
+	if err != nil {
+		c.metrics.reconcileErrors.WithLabelValues(namespace, name).Inc()
+	}
+}
+
+// runHooks notify all hooks that the given key has been processed successfully
+func (c *controller) runHooks(obj interface{}) {
+	key, ok := obj.(string)
+	if !ok {
+		return
+	}
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+	hooks := c.GetHooks()
+	minio, err := c.minioLister.Minios(namespace).Get(name)
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return
+		}
+		last, ok := c.processed.LoadAndDelete(key)
+		if !ok {
+			return
+		}
+		for _, hook := range hooks {
+			hook.OnDelete(last)
+		}
+		return
+	}
+	// workqueue never hands the same key to two workers, so load and store is not racy here
+	_, loaded := c.processed.Load(key)
+	c.processed.Store(key, minio)
+	if loaded {
+		for _, hook := range hooks {
+			hook.OnUpdate(minio)
+		}
+		return
+	}
+	for _, hook := range hooks {
+		hook.OnAdd(minio)
+	}
+}
+
+func (c *controller) markProgress() {
+	atomic.StoreInt64(&c.lastProgress, time.Now().UnixNano())
+}
+
+func (c *controller) Ready() error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		return fmt.Errorf("informer caches have not synced")
+	}
+	return nil
+}
+
+func (c *controller) Live(stallTimeout time.Duration) error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		// still waiting for cache sync, which is covered by readiness
+		return nil
+	}
+	if c.queue.Len() == 0 && atomic.LoadInt32(&c.processing) == 0 {
+		// idle controller is healthy
+		return nil
+	}
+	if stalled := time.Since(time.Unix(0, atomic.LoadInt64(&c.lastProgress))); stalled > stallTimeout {
+		return fmt.Errorf("workqueue has not made progress for %v", stalled)
+	}
+	return nil
+}
+
 func (c *controller) Stop() {
 	klog.Info("Stopping the minio operator controller")
 	c.queue.ShutDown()
@@ -41,6 +41,11 @@ func (h *minioEventHandler) OnUpdate(oldObj, newObj interface{}) {
     }
     if oldMinio.ResourceVersion == newMinio.ResourceVersion {
         h.enqueueFn(newMinio)
+        return
+    }
+    // pausing or resuming takes effect at once, a resumed minio is fully resynced
+    if crapiv1alpha1.IsMinioPaused(oldMinio) != crapiv1alpha1.IsMinioPaused(newMinio) {
+        h.enqueueFn(newMinio)
     }
 }
 
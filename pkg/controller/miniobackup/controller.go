@@ -0,0 +1,48 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package miniobackup
+
+import (
+	"time"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crinformers "github.com/3Xpl0it3r/minio-operator/pkg/client/informers/externalversions"
+	crcontroller "github.com/3Xpl0it3r/minio-operator/pkg/controller"
+	crhandler "github.com/3Xpl0it3r/minio-operator/pkg/controller/miniobackup/handler"
+	backupoperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/miniobackup"
+)
+
+// workqueueName is the name of backup workqueue, it is used as the label of workqueue metrics
+const workqueueName = "miniobackup"
+
+// NewController create a new controller for MinioBackup resources, crInformers are keyed by the namespace they watch.
+// copying objects runs in background, workers only start and finish backups
+func NewController(kubeClientSet kubeclientset.Interface, crClientSet crclientset.Interface, crInformers map[string]crinformers.SharedInformerFactory, resyncPeriod time.Duration) crcontroller.Controller {
+	recorder := crcontroller.NewEventRecorder(kubeClientSet)
+
+	backupListers := crcontroller.NamespacedMinioBackupLister{}
+	minioListers := crcontroller.NamespacedMinioLister{}
+	for namespace, factory := range crInformers {
+		backupListers[namespace] = factory.Miniooperator().V1alpha1().MinioBackups().Lister()
+		minioListers[namespace] = factory.Miniooperator().V1alpha1().Minios().Lister()
+	}
+	c := crcontroller.NewQueueController(workqueueName, "backup", backupoperator.NewOperator(kubeClientSet, crClientSet, backupListers, minioListers, recorder))
+	for _, factory := range crInformers {
+		backupInformer := factory.Miniooperator().V1alpha1().MinioBackups()
+		backupInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewMinioBackupEventHandler(c.Enqueue), resyncPeriod)
+		c.AddCacheSynced(backupInformer.Informer().HasSynced, factory.Miniooperator().V1alpha1().Minios().Informer().HasSynced)
+	}
+	return c
+}
@@ -0,0 +1,50 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package miniobackupschedule
+
+import (
+	"time"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crinformers "github.com/3Xpl0it3r/minio-operator/pkg/client/informers/externalversions"
+	crcontroller "github.com/3Xpl0it3r/minio-operator/pkg/controller"
+	crhandler "github.com/3Xpl0it3r/minio-operator/pkg/controller/miniobackupschedule/handler"
+	scheduleoperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/miniobackupschedule"
+)
+
+// workqueueName is the name of backup schedule workqueue, it is used as the label of workqueue metrics
+const workqueueName = "miniobackupschedule"
+
+// NewController create a new controller for MinioBackupSchedule resources, crInformers are keyed by the namespace they watch.
+// schedules are checked on every resync, so resyncPeriod is the precision of schedule times
+func NewController(kubeClientSet kubeclientset.Interface, crClientSet crclientset.Interface, crInformers map[string]crinformers.SharedInformerFactory, resyncPeriod time.Duration) crcontroller.Controller {
+	recorder := crcontroller.NewEventRecorder(kubeClientSet)
+
+	scheduleListers := crcontroller.NamespacedMinioBackupScheduleLister{}
+	backupListers := crcontroller.NamespacedMinioBackupLister{}
+	for namespace, factory := range crInformers {
+		scheduleListers[namespace] = factory.Miniooperator().V1alpha1().MinioBackupSchedules().Lister()
+		backupListers[namespace] = factory.Miniooperator().V1alpha1().MinioBackups().Lister()
+	}
+	c := crcontroller.NewQueueController(workqueueName, "backup schedule", scheduleoperator.NewOperator(crClientSet, scheduleListers, backupListers, recorder))
+	for _, factory := range crInformers {
+		scheduleInformer := factory.Miniooperator().V1alpha1().MinioBackupSchedules()
+		scheduleInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewMinioBackupScheduleEventHandler(c.Enqueue), resyncPeriod)
+		backupInformer := factory.Miniooperator().V1alpha1().MinioBackups()
+		backupInformer.Informer().AddEventHandler(crhandler.NewMinioBackupEventHandler(c.Enqueue))
+		c.AddCacheSynced(scheduleInformer.Informer().HasSynced, backupInformer.Informer().HasSynced)
+	}
+	return c
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package handler
+
+import (
+	"k8s.io/client-go/tools/cache"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// backupEventHandler enqueue the schedule which created the backup, so retention is applied as soon as a backup finishes
+type backupEventHandler struct {
+	enqueueFn func(key interface{})
+}
+
+func (h *backupEventHandler) OnAdd(obj interface{}) {
+	h.enqueueScheduleForBackup(obj)
+}
+
+func (h *backupEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	h.enqueueScheduleForBackup(newObj)
+}
+
+func (h *backupEventHandler) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	h.enqueueScheduleForBackup(obj)
+}
+
+func (h *backupEventHandler) enqueueScheduleForBackup(obj interface{}) {
+	backup, ok := obj.(*crapiv1alpha1.MinioBackup)
+	if !ok {
+		return
+	}
+	scheduleName, ok := backup.GetLabels()[crconfig.MinioBackupScheduleLabel]
+	if !ok {
+		return
+	}
+	h.enqueueFn(cache.ExplicitKey(backup.GetNamespace() + "/" + scheduleName))
+}
+
+func NewMinioBackupEventHandler(enqueueFn func(key interface{})) *backupEventHandler {
+	return &backupEventHandler{enqueueFn: enqueueFn}
+}
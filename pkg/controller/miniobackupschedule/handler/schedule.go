@@ -0,0 +1,48 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package handler
+
+import (
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type scheduleEventHandler struct {
+	enqueueFn func(key interface{})
+}
+
+func (h *scheduleEventHandler) OnAdd(obj interface{}) {
+	if schedule, ok := obj.(*crapiv1alpha1.MinioBackupSchedule); ok {
+		h.enqueueFn(schedule)
+	}
+}
+
+func (h *scheduleEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	if schedule, ok := newObj.(*crapiv1alpha1.MinioBackupSchedule); ok {
+		h.enqueueFn(schedule)
+	}
+}
+
+func (h *scheduleEventHandler) OnDelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	if schedule, ok := obj.(*crapiv1alpha1.MinioBackupSchedule); ok {
+		h.enqueueFn(schedule)
+	}
+}
+
+func NewMinioBackupScheduleEventHandler(enqueueFn func(key interface{})) *scheduleEventHandler {
+	return &scheduleEventHandler{enqueueFn: enqueueFn}
+}
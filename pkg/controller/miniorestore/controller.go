@@ -0,0 +1,51 @@
+/*
+   Copyright 2022 The minio-operator Authors.
+   Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+package miniorestore
+
+import (
+	"time"
+
+	kubeclientset "k8s.io/client-go/kubernetes"
+
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crinformers "github.com/3Xpl0it3r/minio-operator/pkg/client/informers/externalversions"
+	crcontroller "github.com/3Xpl0it3r/minio-operator/pkg/controller"
+	crhandler "github.com/3Xpl0it3r/minio-operator/pkg/controller/miniorestore/handler"
+	restoreoperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/miniorestore"
+)
+
+// workqueueName is the name of restore workqueue, it is used as the label of workqueue metrics
+const workqueueName = "miniorestore"
+
+// NewController create a new controller for MinioRestore resources, crInformers are keyed by the namespace they watch.
+// copying objects runs in background, workers only start and finish restores
+func NewController(kubeClientSet kubeclientset.Interface, crClientSet crclientset.Interface, crInformers map[string]crinformers.SharedInformerFactory, resyncPeriod time.Duration) crcontroller.Controller {
+	recorder := crcontroller.NewEventRecorder(kubeClientSet)
+
+	restoreListers := crcontroller.NamespacedMinioRestoreLister{}
+	backupListers := crcontroller.NamespacedMinioBackupLister{}
+	minioListers := crcontroller.NamespacedMinioLister{}
+	for namespace, factory := range crInformers {
+		restoreListers[namespace] = factory.Miniooperator().V1alpha1().MinioRestores().Lister()
+		backupListers[namespace] = factory.Miniooperator().V1alpha1().MinioBackups().Lister()
+		minioListers[namespace] = factory.Miniooperator().V1alpha1().Minios().Lister()
+	}
+	c := crcontroller.NewQueueController(workqueueName, "restore", restoreoperator.NewOperator(kubeClientSet, crClientSet, restoreListers, backupListers, minioListers, recorder))
+	for _, factory := range crInformers {
+		restoreInformer := factory.Miniooperator().V1alpha1().MinioRestores()
+		restoreInformer.Informer().AddEventHandlerWithResyncPeriod(crhandler.NewMinioRestoreEventHandler(c.Enqueue), resyncPeriod)
+		c.AddCacheSynced(restoreInformer.Informer().HasSynced,
+			factory.Miniooperator().V1alpha1().MinioBackups().Informer().HasSynced, factory.Miniooperator().V1alpha1().Minios().Informer().HasSynced)
+	}
+	return c
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	apicorev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeclientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	croperator "github.com/3Xpl0it3r/minio-operator/pkg/operator"
+)
+
+// NewEventRecorder return a recorder which logs events and writes them to apiserver
+func NewEventRecorder(kubeClientSet kubeclientset.Interface) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(klog.V(2).Infof)
+	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClientSet.CoreV1().Events(apicorev1.NamespaceAll)})
+	return eventBroadcaster.NewRecorder(scheme.Scheme, apicorev1.EventSource{Component: "Minio-operator"})
+}
+
+// QueueController is a Controller which passes keys of its workqueue to an operator, a controller built on it only
+// registers its informers with Enqueue and AddCacheSynced
+type QueueController struct {
+	Base
+	// kind names the resource in logs and probe errors
+	kind        string
+	queue       workqueue.RateLimitingInterface
+	operator    croperator.Operator
+	cacheSynced []cache.InformerSynced
+
+	// synced is set to 1 after all caches have synced
+	synced int32
+	// processing is the number of items being processed by workers
+	processing int32
+	// lastProgress is the unix nano time when a worker finished an item last time
+	lastProgress int64
+}
+
+// NewQueueController create a QueueController, queueName is used as the label of workqueue metrics
+func NewQueueController(queueName, kind string, operator croperator.Operator) *QueueController {
+	return &QueueController{
+		Base:     NewControllerBase(),
+		kind:     kind,
+		queue:    workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), queueName),
+		operator: operator,
+	}
+}
+
+// AddCacheSynced add informers which must be synced before workers are started
+func (c *QueueController) AddCacheSynced(cacheSynced ...cache.InformerSynced) {
+	c.cacheSynced = append(c.cacheSynced, cacheSynced...)
+}
+
+// Enqueue add the key of obj to workqueue, it is the enqueue func of event handlers
+func (c *QueueController) Enqueue(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.Errorf("failed to get key for %v: %v", obj, err)
+		return
+	}
+	c.queue.Add(key)
+}
+
+func (c *QueueController) Start(worker int, stopCh <-chan struct{}) error {
+	if !cache.WaitForCacheSync(stopCh, c.cacheSynced...) {
+		return fmt.Errorf("timeout wait for cache to be synced")
+	}
+	klog.Infof("%s informers have all synced, Controller Begin to start worker", c.kind)
+	c.markProgress()
+	atomic.StoreInt32(&c.synced, 1)
+	for i := 0; i < worker; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	return nil
+}
+
+func (c *QueueController) runWorker() {
+	defer utilruntime.HandleCrash()
+	for c.processNextItem() {
+	}
+}
+
+func (c *QueueController) processNextItem() bool {
+	obj, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	atomic.AddInt32(&c.processing, 1)
+	defer func() {
+		c.queue.Done(obj)
+		c.markProgress()
+		atomic.AddInt32(&c.processing, -1)
+	}()
+	if err := c.operator.Reconcile(obj); err != nil {
+		c.queue.AddRateLimited(obj)
+		utilruntime.HandleError(err)
+		return true
+	}
+	c.queue.Forget(obj)
+	return true
+}
+
+func (c *QueueController) markProgress() {
+	atomic.StoreInt64(&c.lastProgress, time.Now().UnixNano())
+}
+
+func (c *QueueController) Ready() error {
+	if atomic.LoadInt32(&c.synced) == 0 {
+		return fmt.Errorf("%s informer caches have not synced", c.kind)
+	}
+	return nil
+}
+
+func (c *QueueController) Live(stallTimeout time.Duration) error {
+	if atomic.LoadInt32(&c.synced) == 0 || (c.queue.Len() == 0 && atomic.LoadInt32(&c.processing) == 0) {
+		return nil
+	}
+	if stalled := time.Since(time.Unix(0, atomic.LoadInt64(&c.lastProgress))); stalled > stallTimeout {
+		return fmt.Errorf("%s workqueue has not made progress for %v", c.kind, stalled)
+	}
+	return nil
+}
+
+func (c *QueueController) Stop() {
+	klog.Infof("Stopping the minio %s controller", c.kind)
+	c.queue.ShutDown()
+}
@@ -0,0 +1,162 @@
+package backup
+
+import (
+	crdapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	jsonSchemePropsTypeAsInteger string = "integer"
+	jsonSchemePropsTypeAsString  string = "string"
+	jsonSchemePropsTypeAsObject  string = "object"
+	jsonSchemePropsTypeAsBoolean string = "boolean"
+	jsonSchemePropsTypeAsArray   string = "array"
+)
+
+func NewMinioBackupResourceDefine() *extensionapiv1.CustomResourceDefinition {
+	return newResourceDefine("miniobackups", "miniobackup", "MinioBackup", "MinioBackupList", extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"apiVersion": {Type: jsonSchemePropsTypeAsString},
+			"kind":       {Type: jsonSchemePropsTypeAsString},
+			"metadata":   {Type: jsonSchemePropsTypeAsObject},
+			"spec":       backupSpecSchema(),
+			"status": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"phase":          {Type: jsonSchemePropsTypeAsString},
+					"startTime":      {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"completionTime": {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"location":       {Type: jsonSchemePropsTypeAsString},
+					"base":           {Type: jsonSchemePropsTypeAsString},
+					"buckets":        stringArraySchema(),
+					"objectsCopied":  {Type: jsonSchemePropsTypeAsInteger},
+					"objectsSkipped": {Type: jsonSchemePropsTypeAsInteger},
+					"bytesCopied":    {Type: jsonSchemePropsTypeAsInteger},
+					"message":        {Type: jsonSchemePropsTypeAsString},
+				},
+			},
+		},
+		Required: []string{"apiVersion", "kind", "metadata", "spec"},
+	})
+}
+
+func NewMinioBackupScheduleResourceDefine() *extensionapiv1.CustomResourceDefinition {
+	return newResourceDefine("miniobackupschedules", "miniobackupschedule", "MinioBackupSchedule", "MinioBackupScheduleList", extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"apiVersion": {Type: jsonSchemePropsTypeAsString},
+			"kind":       {Type: jsonSchemePropsTypeAsString},
+			"metadata":   {Type: jsonSchemePropsTypeAsObject},
+			"spec": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"schedule":  {Type: jsonSchemePropsTypeAsString},
+					"suspend":   {Type: jsonSchemePropsTypeAsBoolean},
+					"retention": {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(0)},
+					"template":  backupSpecSchema(),
+				},
+				Required: []string{"schedule", "template"},
+			},
+			"status": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"lastScheduleTime":     {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"lastBackup":           {Type: jsonSchemePropsTypeAsString},
+					"lastSuccessfulBackup": {Type: jsonSchemePropsTypeAsString},
+				},
+			},
+		},
+		Required: []string{"apiVersion", "kind", "metadata", "spec"},
+	})
+}
+
+func newResourceDefine(plural, singular, kind, listKind string, schema extensionapiv1.JSONSchemaProps) *extensionapiv1.CustomResourceDefinition {
+	return &extensionapiv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: plural + "." + crdapiv1alpha1.SchemeGroupVersion.Group,
+		},
+		Spec: extensionapiv1.CustomResourceDefinitionSpec{
+			Group: crdapiv1alpha1.SchemeGroupVersion.Group,
+			Names: extensionapiv1.CustomResourceDefinitionNames{
+				Plural:   plural,
+				Singular: singular,
+				Kind:     kind,
+				ListKind: listKind,
+			},
+			Scope: extensionapiv1.ResourceScope(extensionapiv1.NamespaceScoped),
+			Versions: []extensionapiv1.CustomResourceDefinitionVersion{
+				{
+					Name:    crdapiv1alpha1.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &extensionapiv1.CustomResourceValidation{
+						OpenAPIV3Schema: &schema,
+					},
+					Subresources: &extensionapiv1.CustomResourceSubresources{
+						Status: &extensionapiv1.CustomResourceSubresourceStatus{},
+					},
+				},
+			},
+			PreserveUnknownFields: false,
+		},
+	}
+}
+
+// backupSpecSchema is the schema of MinioBackupSpec, it is shared by backups and the template of schedules
+func backupSpecSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"minio":   {Type: jsonSchemePropsTypeAsString},
+			"buckets": stringArraySchema(),
+			"target":  targetSchema(),
+		},
+		Required: []string{"minio", "target"},
+	}
+}
+
+// targetSchema is the schema of BackupTarget
+func targetSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"s3": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"endpoint":         {Type: jsonSchemePropsTypeAsString},
+					"bucket":           {Type: jsonSchemePropsTypeAsString},
+					"prefix":           {Type: jsonSchemePropsTypeAsString},
+					"region":           {Type: jsonSchemePropsTypeAsString},
+					"secure":           {Type: jsonSchemePropsTypeAsBoolean},
+					"credentialSecret": {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"endpoint", "bucket", "credentialSecret"},
+			},
+			"persistentVolumeClaim": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"claimName": {Type: jsonSchemePropsTypeAsString},
+					"image":     {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"claimName"},
+			},
+		},
+	}
+}
+
+func stringArraySchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsArray,
+		Items: &extensionapiv1.JSONSchemaPropsOrArray{
+			Schema: &extensionapiv1.JSONSchemaProps{
+				Type: jsonSchemePropsTypeAsString,
+			},
+		},
+	}
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
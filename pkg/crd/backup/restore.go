@@ -0,0 +1,54 @@
+package backup
+
+import (
+	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+)
+
+func NewMinioRestoreResourceDefine() *extensionapiv1.CustomResourceDefinition {
+	return newResourceDefine("miniorestores", "miniorestore", "MinioRestore", "MinioRestoreList", extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"apiVersion": {Type: jsonSchemePropsTypeAsString},
+			"kind":       {Type: jsonSchemePropsTypeAsString},
+			"metadata":   {Type: jsonSchemePropsTypeAsObject},
+			"spec": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"minio":  {Type: jsonSchemePropsTypeAsString},
+					"backup": {Type: jsonSchemePropsTypeAsString},
+					"location": {
+						Type: jsonSchemePropsTypeAsObject,
+						Properties: map[string]extensionapiv1.JSONSchemaProps{
+							"target":     targetSchema(),
+							"backupName": {Type: jsonSchemePropsTypeAsString},
+						},
+						Required: []string{"target", "backupName"},
+					},
+					"buckets": stringArraySchema(),
+					"overwrite": {
+						Type: jsonSchemePropsTypeAsString,
+						Enum: []extensionapiv1.JSON{{Raw: []byte(`"Never"`)}, {Raw: []byte(`"Always"`)}, {Raw: []byte(`"IfChanged"`)}},
+					},
+				},
+				Required: []string{"minio"},
+			},
+			"status": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"phase":           {Type: jsonSchemePropsTypeAsString},
+					"startTime":       {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"completionTime":  {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"buckets":         stringArraySchema(),
+					"createdBuckets":  stringArraySchema(),
+					"objectsRestored": {Type: jsonSchemePropsTypeAsInteger},
+					"objectsSkipped":  {Type: jsonSchemePropsTypeAsInteger},
+					"bytesRestored":   {Type: jsonSchemePropsTypeAsInteger},
+					"objectsFailed":   {Type: jsonSchemePropsTypeAsInteger},
+					"failedKeys":      stringArraySchema(),
+					"message":         {Type: jsonSchemePropsTypeAsString},
+				},
+			},
+		},
+		Required: []string{"apiVersion", "kind", "metadata", "spec"},
+	})
+}
@@ -1,6 +1,14 @@
 package crd
 
 import (
+	"context"
+	"fmt"
+
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crapiv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
+	"github.com/3Xpl0it3r/minio-operator/pkg/crd/backup"
 	"github.com/3Xpl0it3r/minio-operator/pkg/crd/minio"
 	"github.com/3Xpl0it3r/minio-operator/pkg/crd/register"
 
@@ -8,17 +16,59 @@ import (
 	extensionclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 )
 
-func InstallCustomResourceDefineToApiServer(extClientSet extensionclientset.Interface) error {
-	crdResourceList := []*extensionapiv1.CustomResourceDefinition{}
-	// register crd object
-	crdResourceList = append(crdResourceList, minio.NewMinioResourceDefine())
-	for _, crObj := range crdResourceList {
-		if err := register.RegisterCRDWithObject(extClientSet, crObj); err != nil {
+// CustomResourceDefinitions return all crd of the operator, v1beta1 of Minio is served if conversion is not nil
+func CustomResourceDefinitions(conversion *extensionapiv1.WebhookClientConfig) []*extensionapiv1.CustomResourceDefinition {
+	return []*extensionapiv1.CustomResourceDefinition{
+		minio.NewMinioResourceDefineWithConversion(conversion),
+		backup.NewMinioBackupResourceDefine(),
+		backup.NewMinioBackupScheduleResourceDefine(),
+		backup.NewMinioRestoreResourceDefine(),
+	}
+}
+
+// InstallCustomResourceDefineToApiServer create or update all crd, v1beta1 of Minio is served if conversion is not nil
+func InstallCustomResourceDefineToApiServer(extClientSet extensionclientset.Interface, conversion *extensionapiv1.WebhookClientConfig) error {
+	if conversion == nil {
+		if err := checkMinioStoredVersions(extClientSet); err != nil {
+			return err
+		}
+	}
+	for _, crObj := range CustomResourceDefinitions(conversion) {
+		if err := register.RegisterOrUpdateCRDWithObject(extClientSet, crObj); err != nil {
+			return err
+		}
+		if err := register.WaitForCRDEstablished(extClientSet, crObj.GetName()); err != nil {
 			return err
 		}
+	}
+	return nil
+}
+
+// WaitForCustomResourceDefineEstablished wait until all crd are established
+func WaitForCustomResourceDefineEstablished(extClientSet extensionclientset.Interface) error {
+	for _, crObj := range CustomResourceDefinitions(nil) {
 		if err := register.WaitForCRDEstablished(extClientSet, crObj.GetName()); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// checkMinioStoredVersions return error if Minio objects may be stored as v1beta1, apiserver refuses to drop a
+// stored version from the crd and those objects can not be served without the conversion webhook
+func checkMinioStoredVersions(extClientSet extensionclientset.Interface) error {
+	crdName := minio.NewMinioResourceDefine().GetName()
+	crd, err := extClientSet.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdName, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	for _, version := range crd.Status.StoredVersions {
+		if version == crapiv1beta1.Version {
+			return fmt.Errorf("crd %s stores %s objects, the conversion webhook must be enabled to serve them", crdName, crapiv1beta1.Version)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package crd
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	apicorev1 "k8s.io/api/core/v1"
+	extensionclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crapiv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	"github.com/3Xpl0it3r/minio-operator/pkg/crd/minio"
+)
+
+// MigrateMinioStorage moves the inline credential of v1alpha1 Minio objects into secrets, rewrites all Minio objects so
+// that they are stored as v1beta1, then drops v1alpha1 from the stored versions of the crd. objects are read as v1alpha1,
+// which apiserver serves without conversion when they are stored as v1alpha1. the conversion webhook must be served
+// before it is called
+func MigrateMinioStorage(extClientSet extensionclientset.Interface, kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface) error {
+	crdName := minio.NewMinioResourceDefine().GetName()
+	minios, err := crClientSet.MiniooperatorV1alpha1().Minios(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var moved int
+	for i := range minios.Items {
+		object := &minios.Items[i]
+		// a referenced secret wins over the inline credential, which is dropped when the object is rewritten
+		if object.Spec.Credential == (crapiv1alpha1.Credential{}) || object.GetAnnotations()[crapiv1alpha1.CredentialSecretAnnotation] != "" {
+			continue
+		}
+		if err = moveInlineCredential(kubeClientSet, crClientSet, object); err != nil {
+			return fmt.Errorf("move credential of minio %s/%s failed: %v", object.GetNamespace(), object.GetName(), err)
+		}
+		moved++
+	}
+	if moved != 0 {
+		klog.Infof("credential of %d minio objects are moved into secrets", moved)
+	}
+
+	crd, err := extClientSet.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if len(crd.Status.StoredVersions) == 1 && crd.Status.StoredVersions[0] == crapiv1beta1.Version {
+		return nil
+	}
+	for i := range minios.Items {
+		// an update without change is enough, apiserver encodes the object with the storage version.
+		// conflicts are ignored, the object has been rewritten by someone else
+		_, err := crClientSet.MiniooperatorV1alpha1().Minios(minios.Items[i].GetNamespace()).Update(context.TODO(), &minios.Items[i], metav1.UpdateOptions{})
+		if err != nil && !k8serror.IsNotFound(err) && !k8serror.IsConflict(err) {
+			return fmt.Errorf("rewrite minio %s/%s failed: %v", minios.Items[i].GetNamespace(), minios.Items[i].GetName(), err)
+		}
+	}
+	klog.Infof("%d minio objects are stored as %s", len(minios.Items), crapiv1beta1.Version)
+
+	crd, err = extClientSet.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	crd = crd.DeepCopy()
+	crd.Status.StoredVersions = []string{crapiv1beta1.Version}
+	_, err = extClientSet.ApiextensionsV1().CustomResourceDefinitions().UpdateStatus(context.TODO(), crd, metav1.UpdateOptions{})
+	return err
+}
+
+// moveInlineCredential save the inline credential of object into secret <name>-root, which is owned by object, and
+// make object reference the secret instead. object is updated in place so it can be rewritten again
+func moveInlineCredential(kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, object *crapiv1alpha1.Minio) error {
+	secretName := object.GetName() + "-root"
+	data := map[string][]byte{
+		crapiv1beta1.CredentialAccessKey: []byte(object.Spec.Credential.AccessKey),
+		crapiv1beta1.CredentialSecretKey: []byte(object.Spec.Credential.SecretKey),
+	}
+	secrets := kubeClientSet.CoreV1().Secrets(object.GetNamespace())
+	_, err := secrets.Create(context.TODO(), &apicorev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            secretName,
+			Namespace:       object.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(object, crapiv1alpha1.SchemeGroupVersion.WithKind("Minio"))},
+		},
+		Type: apicorev1.SecretTypeOpaque,
+		Data: data,
+	}, metav1.CreateOptions{})
+	if k8serror.IsAlreadyExists(err) {
+		// created by a migration which failed before the object was updated
+		var existing *apicorev1.Secret
+		if existing, err = secrets.Get(context.TODO(), secretName, metav1.GetOptions{}); err == nil && !reflect.DeepEqual(existing.Data, data) {
+			err = fmt.Errorf("secret %s already exists with another credential", secretName)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	objectCopy := object.DeepCopy()
+	objectCopy.Spec.Credential = crapiv1alpha1.Credential{}
+	if objectCopy.Annotations == nil {
+		objectCopy.Annotations = map[string]string{}
+	}
+	objectCopy.Annotations[crapiv1alpha1.CredentialSecretAnnotation] = secretName
+	updated, err := crClientSet.MiniooperatorV1alpha1().Minios(object.GetNamespace()).Update(context.TODO(), objectCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	*object = *updated
+	return nil
+}
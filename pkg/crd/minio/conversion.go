@@ -0,0 +1,54 @@
+package minio
+
+import (
+	"encoding/json"
+	"fmt"
+
+	crdapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crdapiv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConvertMinio converts a Minio in json to desiredAPIVersion, objects are converted through the hub version v1beta1
+func ConvertMinio(object []byte, desiredAPIVersion string) ([]byte, error) {
+	var typeMeta metav1.TypeMeta
+	if err := json.Unmarshal(object, &typeMeta); err != nil {
+		return nil, err
+	}
+	if typeMeta.Kind != "Minio" {
+		return nil, fmt.Errorf("unexpected kind %q", typeMeta.Kind)
+	}
+	if typeMeta.APIVersion == desiredAPIVersion {
+		return object, nil
+	}
+
+	hub := new(crdapiv1beta1.Minio)
+	switch typeMeta.APIVersion {
+	case crdapiv1beta1.SchemeGroupVersion.String():
+		if err := json.Unmarshal(object, hub); err != nil {
+			return nil, err
+		}
+	case crdapiv1alpha1.SchemeGroupVersion.String():
+		spoke := new(crdapiv1alpha1.Minio)
+		if err := json.Unmarshal(object, spoke); err != nil {
+			return nil, err
+		}
+		if err := spoke.ConvertTo(hub); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unexpected apiVersion %q", typeMeta.APIVersion)
+	}
+
+	switch desiredAPIVersion {
+	case crdapiv1beta1.SchemeGroupVersion.String():
+		return json.Marshal(hub)
+	case crdapiv1alpha1.SchemeGroupVersion.String():
+		spoke := new(crdapiv1alpha1.Minio)
+		if err := spoke.ConvertFrom(hub); err != nil {
+			return nil, err
+		}
+		return json.Marshal(spoke)
+	}
+	return nil, fmt.Errorf("unexpected desired apiVersion %q", desiredAPIVersion)
+}
@@ -2,6 +2,7 @@ package minio
 
 import (
 	crdapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crdapiv1beta1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1beta1"
 	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -12,9 +13,17 @@ const (
 	jsonSchemePropsTypeAsObject  string = "object"
 	jsonSchemePropsTypesAsNumber string = "number"
 	jsonSchemePropsTypeAsArray   string = "array"
+	jsonSchemePropsTypeAsBoolean string = "boolean"
 )
 
+// NewMinioResourceDefine return the crd of Minio which serves and stores v1alpha1 only
 func NewMinioResourceDefine() *extensionapiv1.CustomResourceDefinition {
+	return NewMinioResourceDefineWithConversion(nil)
+}
+
+// NewMinioResourceDefineWithConversion return the crd of Minio, if the conversion webhook is given v1beta1 is served
+// too and becomes the storage version. objects are converted between versions by the webhook
+func NewMinioResourceDefineWithConversion(webhook *extensionapiv1.WebhookClientConfig) *extensionapiv1.CustomResourceDefinition {
 	crd := &extensionapiv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "minios" + "." + crdapiv1alpha1.SchemeGroupVersion.Group,
@@ -22,64 +31,475 @@ func NewMinioResourceDefine() *extensionapiv1.CustomResourceDefinition {
 		Spec: extensionapiv1.CustomResourceDefinitionSpec{
 			Group: crdapiv1alpha1.SchemeGroupVersion.Group,
 			Names: extensionapiv1.CustomResourceDefinitionNames{
-				Plural:   "minios",
-				Singular: "minio",
-				Kind:     "Minio",
-				ListKind: "MinioList",
+				Plural:     "minios",
+				Singular:   "minio",
+				Kind:       "Minio",
+				ListKind:   "MinioList",
+				ShortNames: []string{"mi"},
+				Categories: []string{"all"},
 			},
 			Scope: extensionapiv1.ResourceScope(extensionapiv1.NamespaceScoped),
 			Versions: []extensionapiv1.CustomResourceDefinitionVersion{
-				{
-					Name:    crdapiv1alpha1.Version,
-					Served:  true,
-					Storage: true,
-					Schema: &extensionapiv1.CustomResourceValidation{
-						OpenAPIV3Schema: &extensionapiv1.JSONSchemaProps{
-							Type: jsonSchemePropsTypeAsObject,
-							Properties: map[string]extensionapiv1.JSONSchemaProps{
-								"apiVersion": {Type: jsonSchemePropsTypeAsString},
-								"kind":       {Type: jsonSchemePropsTypeAsString},
-								"metadata":   {Type: jsonSchemePropsTypeAsObject},
-								"spec": {
-									Type: jsonSchemePropsTypeAsObject,
-									Properties: map[string]extensionapiv1.JSONSchemaProps{
-										"replicas": {Type: jsonSchemePropsTypeAsInteger},
-										"image":    {Type: jsonSchemePropsTypeAsString},
-										"hostpath": {Type: jsonSchemePropsTypeAsString},
-										"credential": {
-											Type: jsonSchemePropsTypeAsObject,
-											Properties: map[string]extensionapiv1.JSONSchemaProps{
-												"access_key": {Type: jsonSchemePropsTypeAsString},
-												"secret_key": {Type: jsonSchemePropsTypeAsString},
-											},
-										},
-										"buckets": {
-											Type: jsonSchemePropsTypeAsArray,
-											Items: &extensionapiv1.JSONSchemaPropsOrArray{
-												Schema: &extensionapiv1.JSONSchemaProps{
-													Type: jsonSchemePropsTypeAsString,
-												},
-											},
-										},
-									},
-								},
-								"status": {
-									Type: jsonSchemePropsTypeAsObject,
-									Properties: map[string]extensionapiv1.JSONSchemaProps{
-										"inited": {Type: jsonSchemePropsTypeAsString},
-									},
+				minioVersion(crdapiv1alpha1.Version, minioV1alpha1SpecProperties(), webhook == nil),
+			},
+			PreserveUnknownFields: false,
+		},
+	}
+	if webhook != nil {
+		crd.Spec.Versions = append(crd.Spec.Versions, minioVersion(crdapiv1beta1.Version, minioV1beta1SpecProperties(), true))
+		crd.Spec.Conversion = &extensionapiv1.CustomResourceConversion{
+			Strategy: extensionapiv1.WebhookConverter,
+			Webhook: &extensionapiv1.WebhookConversion{
+				ClientConfig:             webhook,
+				ConversionReviewVersions: []string{"v1"},
+			},
+		}
+	}
+	return crd
+}
+
+// minioVersion return a version of Minio whose spec has the given properties, status is the same in all versions
+func minioVersion(name string, specProperties map[string]extensionapiv1.JSONSchemaProps, storage bool) extensionapiv1.CustomResourceDefinitionVersion {
+	return extensionapiv1.CustomResourceDefinitionVersion{
+		Name:    name,
+		Served:  true,
+		Storage: storage,
+		Schema: &extensionapiv1.CustomResourceValidation{
+			OpenAPIV3Schema: &extensionapiv1.JSONSchemaProps{
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"apiVersion": {Type: jsonSchemePropsTypeAsString},
+					"kind":       {Type: jsonSchemePropsTypeAsString},
+					"metadata":   {Type: jsonSchemePropsTypeAsObject},
+					"spec": {
+						Type:       jsonSchemePropsTypeAsObject,
+						Properties: specProperties,
+					},
+					"status": {
+						Type: jsonSchemePropsTypeAsObject,
+						Properties: map[string]extensionapiv1.JSONSchemaProps{
+							"inited":                 {Type: jsonSchemePropsTypeAsString},
+							"credentialHash":         {Type: jsonSchemePropsTypeAsString},
+							"credentialRotationTime": {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+							"conditions":             conditionsSchema(),
+							"readyReplicas":          {Type: jsonSchemePropsTypeAsInteger},
+							"phase":                  {Type: jsonSchemePropsTypeAsString},
+							"selector":               {Type: jsonSchemePropsTypeAsString},
+						},
+					},
+				},
+				Required: []string{"apiVersion", "kind", "metadata", "spec"},
+			},
+		},
+		Subresources: &extensionapiv1.CustomResourceSubresources{
+			Status: &extensionapiv1.CustomResourceSubresourceStatus{},
+			// kubectl scale and autoscalers change spec.replicas through the scale subresource
+			Scale: &extensionapiv1.CustomResourceSubresourceScale{
+				SpecReplicasPath:   ".spec.replicas",
+				StatusReplicasPath: ".status.readyReplicas",
+				LabelSelectorPath:  stringPtr(".status.selector"),
+			},
+		},
+		AdditionalPrinterColumns: []extensionapiv1.CustomResourceColumnDefinition{
+			{Name: "Replicas", Type: jsonSchemePropsTypeAsInteger, JSONPath: ".spec.replicas"},
+			{Name: "Ready", Type: jsonSchemePropsTypeAsInteger, JSONPath: ".status.readyReplicas"},
+			{Name: "Image", Type: jsonSchemePropsTypeAsString, JSONPath: ".spec.image"},
+			{Name: "Phase", Type: jsonSchemePropsTypeAsString, JSONPath: ".status.phase"},
+			{Name: "Age", Type: "date", JSONPath: ".metadata.creationTimestamp"},
+		},
+	}
+}
+
+// minioV1alpha1SpecProperties is the spec of v1alpha1 which embeds the root credential
+func minioV1alpha1SpecProperties() map[string]extensionapiv1.JSONSchemaProps {
+	properties := minioSectionProperties()
+	properties["hostpath"] = extensionapiv1.JSONSchemaProps{Type: jsonSchemePropsTypeAsString}
+	properties["credential"] = extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"access_key": {Type: jsonSchemePropsTypeAsString},
+			"secret_key": {Type: jsonSchemePropsTypeAsString},
+		},
+	}
+	properties["port"] = extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"http_port": {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(65535)},
+			"apiport":   {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(65535)},
+			"nodeport":  {Type: jsonSchemePropsTypeAsInteger},
+		},
+	}
+	return properties
+}
+
+// minioV1beta1SpecProperties is the spec of v1beta1 which references the root credential by secret
+func minioV1beta1SpecProperties() map[string]extensionapiv1.JSONSchemaProps {
+	properties := minioSectionProperties()
+	properties["hostPath"] = extensionapiv1.JSONSchemaProps{Type: jsonSchemePropsTypeAsString}
+	properties["credential"] = extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"secretName": {Type: jsonSchemePropsTypeAsString},
+		},
+	}
+	properties["ports"] = extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"api":      {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(65535)},
+			"console":  {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(65535)},
+			"nodePort": {Type: jsonSchemePropsTypeAsInteger},
+		},
+	}
+	return properties
+}
+
+// minioSectionProperties are the spec properties which have the same shape in all versions
+func minioSectionProperties() map[string]extensionapiv1.JSONSchemaProps {
+	return map[string]extensionapiv1.JSONSchemaProps{
+		"replicas":        {Type: jsonSchemePropsTypeAsInteger},
+		"image":           {Type: jsonSchemePropsTypeAsString},
+		"buckets":         stringArraySchema(),
+		"siteReplication": siteReplicationSchema(),
+		"console":         consoleSchema(),
+		"identity":        identitySchema(),
+		"encryption":      encryptionSchema(),
+		"logging":         loggingSchema(),
+		"monitoring":      monitoringSchema(),
+		"networkPolicy":   networkPolicySchema(),
+		"settings":        settingsSchema(),
+		"env":             preservedObjectArraySchema(),
+		"envFrom":         preservedObjectArraySchema(),
+	}
+}
+
+// siteReplicationSchema is the schema of SiteReplication
+func siteReplicationSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"name":     {Type: jsonSchemePropsTypeAsString},
+			"endpoint": {Type: jsonSchemePropsTypeAsString},
+			"image":    {Type: jsonSchemePropsTypeAsString},
+			"peers": {
+				Type: jsonSchemePropsTypeAsArray,
+				Items: &extensionapiv1.JSONSchemaPropsOrArray{
+					Schema: &extensionapiv1.JSONSchemaProps{
+						Type: jsonSchemePropsTypeAsObject,
+						Properties: map[string]extensionapiv1.JSONSchemaProps{
+							"name": {Type: jsonSchemePropsTypeAsString},
+							"minio": {
+								Type: jsonSchemePropsTypeAsObject,
+								Properties: map[string]extensionapiv1.JSONSchemaProps{
+									"name":      {Type: jsonSchemePropsTypeAsString},
+									"namespace": {Type: jsonSchemePropsTypeAsString},
 								},
+								Required: []string{"name"},
 							},
-							Required: []string{"apiVersion", "kind", "metadata", "spec"},
+							"endpoint":         {Type: jsonSchemePropsTypeAsString},
+							"credentialSecret": {Type: jsonSchemePropsTypeAsString},
+						},
+					},
+				},
+			},
+		},
+		Required: []string{"peers"},
+	}
+}
+
+// conditionsSchema is the schema of []metav1.Condition
+func conditionsSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsArray,
+		Items: &extensionapiv1.JSONSchemaPropsOrArray{
+			Schema: &extensionapiv1.JSONSchemaProps{
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"type":               {Type: jsonSchemePropsTypeAsString},
+					"status":             {Type: jsonSchemePropsTypeAsString},
+					"observedGeneration": {Type: jsonSchemePropsTypeAsInteger},
+					"lastTransitionTime": {Type: jsonSchemePropsTypeAsString, Format: "date-time"},
+					"reason":             {Type: jsonSchemePropsTypeAsString},
+					"message":            {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"type", "status", "lastTransitionTime", "reason", "message"},
+			},
+		},
+	}
+}
+
+// settingsSchema is the schema of ServerSettings
+func settingsSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"browser":              {Type: jsonSchemePropsTypeAsBoolean},
+			"region":               {Type: jsonSchemePropsTypeAsString},
+			"storageClassStandard": {Type: jsonSchemePropsTypeAsString},
+			"storageClassRRS":      {Type: jsonSchemePropsTypeAsString},
+			"prometheusAuthType":   {Type: jsonSchemePropsTypeAsString, Enum: enum("jwt", "public")},
+			"scannerSpeed":         {Type: jsonSchemePropsTypeAsString, Enum: enum("fastest", "fast", "default", "slow", "slowest")},
+			"compression": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"enabled":         {Type: jsonSchemePropsTypeAsBoolean},
+					"allowEncryption": {Type: jsonSchemePropsTypeAsBoolean},
+					"extensions":      stringArraySchema(),
+					"mimeTypes":       stringArraySchema(),
+				},
+			},
+		},
+	}
+}
+
+// preservedObjectArraySchema is the schema of an array of kubernetes core types, they are validated by the pods
+func preservedObjectArraySchema() extensionapiv1.JSONSchemaProps {
+	preserve := true
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsArray,
+		Items: &extensionapiv1.JSONSchemaPropsOrArray{
+			Schema: &extensionapiv1.JSONSchemaProps{
+				Type:                   jsonSchemePropsTypeAsObject,
+				XPreserveUnknownFields: &preserve,
+			},
+		},
+	}
+}
+
+func stringArraySchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsArray,
+		Items: &extensionapiv1.JSONSchemaPropsOrArray{
+			Schema: &extensionapiv1.JSONSchemaProps{
+				Type: jsonSchemePropsTypeAsString,
+			},
+		},
+	}
+}
+
+func enum(values ...string) []extensionapiv1.JSON {
+	var ret []extensionapiv1.JSON
+	for _, value := range values {
+		ret = append(ret, extensionapiv1.JSON{Raw: []byte(`"` + value + `"`)})
+	}
+	return ret
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+// consoleSchema is the schema of ConsoleSpec
+func consoleSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"image":       {Type: jsonSchemePropsTypeAsString},
+			"replicas":    {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(0)},
+			"port":        {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(1), Maximum: float64Ptr(65535)},
+			"serviceType": {Type: jsonSchemePropsTypeAsString, Enum: enum("ClusterIP", "NodePort", "LoadBalancer")},
+			"ingress": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"host":             {Type: jsonSchemePropsTypeAsString},
+					"ingressClassName": {Type: jsonSchemePropsTypeAsString},
+					"tlsSecret":        {Type: jsonSchemePropsTypeAsString},
+					"annotations": {
+						Type:                 jsonSchemePropsTypeAsObject,
+						AdditionalProperties: &extensionapiv1.JSONSchemaPropsOrBool{Schema: &extensionapiv1.JSONSchemaProps{Type: jsonSchemePropsTypeAsString}},
+					},
+				},
+				Required: []string{"host"},
+			},
+			"oidc": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"configURL":    {Type: jsonSchemePropsTypeAsString},
+					"clientID":     {Type: jsonSchemePropsTypeAsString},
+					"clientSecret": secretKeySelectorSchema(),
+					"scopes":       stringArraySchema(),
+					"callbackURL":  {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"configURL", "clientID", "clientSecret"},
+			},
+			"ldap": {Type: jsonSchemePropsTypeAsBoolean},
+		},
+	}
+}
+
+// secretKeySelectorSchema is the schema of corev1.SecretKeySelector
+func secretKeySelectorSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"name":     {Type: jsonSchemePropsTypeAsString},
+			"key":      {Type: jsonSchemePropsTypeAsString},
+			"optional": {Type: jsonSchemePropsTypeAsBoolean},
+		},
+		Required: []string{"key"},
+	}
+}
+
+// identitySchema is the schema of IdentitySpec
+func identitySchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"openid": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"configURL":    {Type: jsonSchemePropsTypeAsString},
+					"clientID":     {Type: jsonSchemePropsTypeAsString},
+					"clientSecret": secretKeySelectorSchema(),
+					"claimName":    {Type: jsonSchemePropsTypeAsString},
+					"scopes":       stringArraySchema(),
+					"redirectURI":  {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"configURL", "clientID", "clientSecret"},
+			},
+			"ldap": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"serverAddr":         {Type: jsonSchemePropsTypeAsString},
+					"lookupBindSecret":   {Type: jsonSchemePropsTypeAsString},
+					"userDNSearchBaseDN": {Type: jsonSchemePropsTypeAsString},
+					"userDNSearchFilter": {Type: jsonSchemePropsTypeAsString},
+					"groupSearchBaseDN":  {Type: jsonSchemePropsTypeAsString},
+					"groupSearchFilter":  {Type: jsonSchemePropsTypeAsString},
+					"tls": {
+						Type: jsonSchemePropsTypeAsObject,
+						Properties: map[string]extensionapiv1.JSONSchemaProps{
+							"insecureSkipVerify": {Type: jsonSchemePropsTypeAsBoolean},
+							"startTLS":           {Type: jsonSchemePropsTypeAsBoolean},
+							"insecure":           {Type: jsonSchemePropsTypeAsBoolean},
 						},
 					},
-					Subresources: &extensionapiv1.CustomResourceSubresources{
-						Status: &extensionapiv1.CustomResourceSubresourceStatus{},
+				},
+				Required: []string{"serverAddr", "lookupBindSecret", "userDNSearchBaseDN", "userDNSearchFilter"},
+			},
+		},
+	}
+}
+
+// encryptionSchema is the schema of EncryptionSpec
+func encryptionSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"kes": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"image":            {Type: jsonSchemePropsTypeAsString},
+					"replicas":         {Type: jsonSchemePropsTypeAsInteger, Minimum: float64Ptr(0)},
+					"serverCertSecret": {Type: jsonSchemePropsTypeAsString},
+					"configSecret":     {Type: jsonSchemePropsTypeAsString},
+				},
+				Required: []string{"serverCertSecret", "configSecret"},
+			},
+			"endpoint":         {Type: jsonSchemePropsTypeAsString},
+			"keyName":          {Type: jsonSchemePropsTypeAsString},
+			"clientCertSecret": {Type: jsonSchemePropsTypeAsString},
+			"caSecret":         {Type: jsonSchemePropsTypeAsString},
+			"buckets": {
+				Type: jsonSchemePropsTypeAsArray,
+				Items: &extensionapiv1.JSONSchemaPropsOrArray{Schema: &extensionapiv1.JSONSchemaProps{
+					Type: jsonSchemePropsTypeAsObject,
+					Properties: map[string]extensionapiv1.JSONSchemaProps{
+						"bucket":    {Type: jsonSchemePropsTypeAsString},
+						"algorithm": {Type: jsonSchemePropsTypeAsString, Enum: enum("SSE-S3", "SSE-KMS")},
+						"keyID":     {Type: jsonSchemePropsTypeAsString},
+					},
+					Required: []string{"bucket"},
+				}},
+			},
+		},
+		Required: []string{"keyName", "clientCertSecret"},
+	}
+}
+
+// quantityPattern is the pattern of resource.Quantity which kubernetes uses for int-or-string quantities
+const quantityPattern = `^(\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))(([KMGTPE]i)|[numkMGTPE]|([eE](\+|-)?(([0-9]+(\.[0-9]*)?)|(\.[0-9]+))))?$`
+
+// loggingSchema is the schema of LoggingSpec
+func loggingSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"audit":  logTargetsSchema(),
+			"server": logTargetsSchema(),
+			"collector": {
+				Type: jsonSchemePropsTypeAsObject,
+				Properties: map[string]extensionapiv1.JSONSchemaProps{
+					"image":            {Type: jsonSchemePropsTypeAsString},
+					"size":             {Type: jsonSchemePropsTypeAsString, Pattern: quantityPattern},
+					"storageClassName": {Type: jsonSchemePropsTypeAsString},
+				},
+			},
+		},
+	}
+}
+
+// logTargetsSchema is the schema of []LogTarget
+func logTargetsSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsArray,
+		Items: &extensionapiv1.JSONSchemaPropsOrArray{Schema: &extensionapiv1.JSONSchemaProps{
+			Type: jsonSchemePropsTypeAsObject,
+			Properties: map[string]extensionapiv1.JSONSchemaProps{
+				"name": {Type: jsonSchemePropsTypeAsString, Pattern: "^[a-zA-Z0-9_-]+$"},
+				"webhook": {
+					Type: jsonSchemePropsTypeAsObject,
+					Properties: map[string]extensionapiv1.JSONSchemaProps{
+						"endpoint":  {Type: jsonSchemePropsTypeAsString},
+						"authToken": secretKeySelectorSchema(),
+					},
+					Required: []string{"endpoint"},
+				},
+				"kafka": {
+					Type: jsonSchemePropsTypeAsObject,
+					Properties: map[string]extensionapiv1.JSONSchemaProps{
+						"brokers":       stringArraySchema(),
+						"topic":         {Type: jsonSchemePropsTypeAsString},
+						"tls":           {Type: jsonSchemePropsTypeAsBoolean},
+						"saslSecret":    {Type: jsonSchemePropsTypeAsString},
+						"saslMechanism": {Type: jsonSchemePropsTypeAsString, Enum: enum("plain", "sha256", "sha512")},
 					},
+					Required: []string{"brokers", "topic"},
 				},
 			},
-			PreserveUnknownFields: false,
+			Required: []string{"name"},
+		}},
+	}
+}
+
+// monitoringSchema is the schema of MonitoringSpec
+func monitoringSchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"public":         {Type: jsonSchemePropsTypeAsBoolean},
+			"serviceMonitor": {Type: jsonSchemePropsTypeAsBoolean},
+			"podMonitor":     {Type: jsonSchemePropsTypeAsBoolean},
+			"labels": {
+				Type:                 jsonSchemePropsTypeAsObject,
+				AdditionalProperties: &extensionapiv1.JSONSchemaPropsOrBool{Schema: &extensionapiv1.JSONSchemaProps{Type: jsonSchemePropsTypeAsString}},
+			},
+			"interval": {Type: jsonSchemePropsTypeAsString},
 		},
 	}
-	return crd
+}
+
+// networkPolicySchema is the schema of NetworkPolicySpec, peers are validated by kubernetes when policies are created
+func networkPolicySchema() extensionapiv1.JSONSchemaProps {
+	return extensionapiv1.JSONSchemaProps{
+		Type: jsonSchemePropsTypeAsObject,
+		Properties: map[string]extensionapiv1.JSONSchemaProps{
+			"api":      preservedObjectArraySchema(),
+			"console":  preservedObjectArraySchema(),
+			"operator": preservedObjectArraySchema(),
+		},
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
 }
@@ -7,6 +7,7 @@ import (
 
 	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extensionclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/util/yaml"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +35,22 @@ func RegisterCRDWithObject(extClient extensionclientset.Interface, crdObj *exten
 	return nil
 }
 
+// RegisterOrUpdateCRDWithObject register crd, if the crd is already existed then update its spec
+func RegisterOrUpdateCRDWithObject(extClient extensionclientset.Interface, crdObj *extensionapiv1.CustomResourceDefinition) error {
+	err := RegisterCRDWithObject(extClient, crdObj)
+	if err == nil || !k8serror.IsAlreadyExists(err) {
+		return err
+	}
+	existed, err := extClient.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), crdObj.GetName(), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	existed = existed.DeepCopy()
+	existed.Spec = crdObj.Spec
+	_, err = extClient.ApiextensionsV1().CustomResourceDefinitions().Update(context.TODO(), existed, metav1.UpdateOptions{})
+	return err
+}
+
 func UnregisterCRD(extClientSet extensionclientset.Interface, crdName string) error {
 	return extClientSet.ApiextensionsV1().CustomResourceDefinitions().Delete(context.TODO(), crdName, metav1.DeleteOptions{})
 }
@@ -1,19 +1,18 @@
 package crd
 
 import (
-	"github.com/3Xpl0it3r/minio-operator/pkg/crd/minio"
 	"github.com/3Xpl0it3r/minio-operator/pkg/crd/register"
 
-	extensionapiv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	extensionclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 )
 
+// UnInstallCustomResourceDefineToApiServer delete all crd, all custom resources are deleted with them
 func UnInstallCustomResourceDefineToApiServer(extClientSet extensionclientset.Interface) error {
-	crdResourceList := []*extensionapiv1.CustomResourceDefinition{}
-	// register crd object
-	crdResourceList = append(crdResourceList, minio.NewMinioResourceDefine())
-	for _, crObj := range crdResourceList {
-		register.UnregisterCRD(extClientSet, crObj.GetName())
+	for _, crObj := range CustomResourceDefinitions(nil) {
+		if err := register.UnregisterCRD(extClientSet, crObj.GetName()); err != nil && !k8serror.IsNotFound(err) {
+			return err
+		}
 	}
 	return nil
 }
@@ -122,7 +122,6 @@ func (f *Fixture) AddCustomResourceLister(cr runtime.Object) error {
 	default:
 		return fmt.Errorf("Unexpect Custom Resource Type %s %s ", cr.GetObjectKind().GroupVersionKind().Kind, cr.GetObjectKind().GroupVersionKind().GroupVersion())
 	}
-	return nil
 }
 
 // add expect actions
@@ -3,3 +3,60 @@ package minio
 const (
     MinioPodIndex = "index"
 )
+
+// reasons of events recorded on minio object
+const (
+	EventReasonPodCreated             = "PodCreated"
+	EventReasonPodCreateFailed        = "PodCreateFailed"
+	EventReasonPodNotReady            = "PodNotReady"
+	EventReasonNodeSelected           = "NodeSelected"
+	EventReasonNoNodeAvailable        = "NoNodeAvailable"
+	EventReasonServiceCreated         = "ServiceCreated"
+	EventReasonServiceRepaired        = "ServiceRepaired"
+	EventReasonServiceSyncFailed      = "ServiceSyncFailed"
+	EventReasonBucketCreateFailed     = "BucketCreateFailed"
+	EventReasonApplicationNotReady    = "ApplicationNotReady"
+	EventReasonStatusChanged          = "StatusChanged"
+	EventReasonCredentialRotating     = "CredentialRotating"
+	EventReasonCredentialRotated      = "CredentialRotated"
+	EventReasonSiteReplicationJob     = "SiteReplicationJobCreated"
+	EventReasonSettingsChanged        = "SettingsChanged"
+	EventReasonPortsChanged           = "PortsChanged"
+	EventReasonConsoleSynced          = "ConsoleSynced"
+	EventReasonConsoleRemoved         = "ConsoleRemoved"
+	EventReasonInvalidIdentity        = "InvalidIdentity"
+	EventReasonKESSynced              = "KESSynced"
+	EventReasonKESRemoved             = "KESRemoved"
+	EventReasonInvalidLogging         = "InvalidLogging"
+	EventReasonLogCollectorSynced     = "LogCollectorSynced"
+	EventReasonLogCollectorRemoved    = "LogCollectorRemoved"
+	EventReasonDisruptionBudgetSynced = "DisruptionBudgetSynced"
+	EventReasonNetworkPolicySynced    = "NetworkPolicySynced"
+	EventReasonInvalidCredential      = "InvalidCredential"
+	EventReasonScaleDownRefused       = "ScaleDownRefused"
+	EventReasonInvalidReplicas        = "InvalidReplicas"
+	EventReasonStaleMembersRemoved    = "StaleMembersRemoved"
+)
+
+// reasons of the SiteReplicationHealthy condition, changes of the condition are recorded as events with the same reason
+const (
+	SiteReplicationReasonInvalidPeer     = "InvalidPeer"
+	SiteReplicationReasonAdminAPIFailed  = "AdminAPIFailed"
+	SiteReplicationReasonConfiguring     = "Configuring"
+	SiteReplicationReasonConfigureFailed = "ConfigureFailed"
+	SiteReplicationReasonSiteOffline     = "SiteOffline"
+	SiteReplicationReasonReplicated      = "Replicated"
+)
+
+// reasons of the EncryptionReady condition
+const (
+	EncryptionReasonKeyUnavailable = "KeyUnavailable"
+	EncryptionReasonBucketFailed   = "BucketEncryptionFailed"
+	EncryptionReasonReady          = "Ready"
+)
+
+// reasons of the Paused condition
+const (
+	PausedReasonAnnotated = "PausedByAnnotation"
+	PausedReasonResumed   = "Resumed"
+)
@@ -0,0 +1,315 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// keys of the console secret, console encrypts its session tokens with them
+const (
+	consolePassphraseKey = "passphrase"
+	consoleSaltKey       = "salt"
+)
+
+// syncConsole make the console deployment, service, ingress and secret match spec.console, they are removed
+// when spec.console is not set
+func (o *operator) syncConsole(minio *crapiv1alpha1.Minio) error {
+	if minio.Spec.Console == nil {
+		return o.removeConsole(minio)
+	}
+	if err := o.syncConsoleSecret(minio); err != nil {
+		return fmt.Errorf("sync console secret failed: %v", err)
+	}
+	deploymentChanged, err := o.syncDeployment(newConsoleDeployment(minio))
+	if err != nil {
+		return fmt.Errorf("sync console deployment failed: %v", err)
+	}
+	if _, err = o.syncService(minio, newConsoleService(minio)); err != nil {
+		return fmt.Errorf("sync console service failed: %v", err)
+	}
+	ingressChanged, err := o.syncConsoleIngress(minio)
+	if err != nil {
+		return fmt.Errorf("sync console ingress failed: %v", err)
+	}
+	if deploymentChanged || ingressChanged {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonConsoleSynced, "Console %s is synced", getConsoleName(minio))
+	}
+	return nil
+}
+
+// syncConsoleSecret create the secret holding random keys of console, the keys are never changed so sessions
+// survive restarts of console
+func (o *operator) syncConsoleSecret(minio *crapiv1alpha1.Minio) error {
+	_, err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Get(context.TODO(), getConsoleName(minio), metav1.GetOptions{})
+	if err == nil || !k8serror.IsNotFound(err) {
+		return err
+	}
+	passphrase, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+	salt, err := randomHex(32)
+	if err != nil {
+		return err
+	}
+	_, err = o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Create(context.TODO(), &apicorev1.Secret{
+		ObjectMeta: newConsoleObjectMeta(minio),
+		Type:       apicorev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			consolePassphraseKey: []byte(passphrase),
+			consoleSaltKey:       []byte(salt),
+		},
+	}, metav1.CreateOptions{})
+	if k8serror.IsAlreadyExists(err) {
+		return nil
+	}
+	return err
+}
+
+// syncConsoleIngress create, update or delete the console ingress, it returns true if the ingress is changed
+func (o *operator) syncConsoleIngress(minio *crapiv1alpha1.Minio) (bool, error) {
+	ingresses := o.kubeClientSet.NetworkingV1().Ingresses(minio.GetNamespace())
+	ingress, err := ingresses.Get(context.TODO(), getConsoleName(minio), metav1.GetOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return false, err
+	}
+	exists := err == nil
+	if minio.Spec.Console.Ingress == nil {
+		if !exists {
+			return false, nil
+		}
+		err = ingresses.Delete(context.TODO(), ingress.GetName(), metav1.DeleteOptions{})
+		return err == nil, ignoreNotFound(err)
+	}
+	desired := newConsoleIngress(minio)
+	if !exists {
+		_, err = ingresses.Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if ingress.GetAnnotations()[crconfig.MinioSpecHash] == desired.GetAnnotations()[crconfig.MinioSpecHash] {
+		return false, nil
+	}
+	ingressCopy := ingress.DeepCopy()
+	ingressCopy.Labels = desired.Labels
+	ingressCopy.Annotations = desired.Annotations
+	ingressCopy.Spec = desired.Spec
+	_, err = ingresses.Update(context.TODO(), ingressCopy, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// removeConsole delete all console objects
+func (o *operator) removeConsole(minio *crapiv1alpha1.Minio) error {
+	name := getConsoleName(minio)
+	removed, err := o.removeComponent(minio.GetNamespace(), name, func() error {
+		if err := o.kubeClientSet.NetworkingV1().Ingresses(minio.GetNamespace()).Delete(context.TODO(), name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return err
+		}
+		return ignoreNotFound(o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Delete(context.TODO(), name, metav1.DeleteOptions{}))
+	})
+	if removed {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonConsoleRemoved, "Console %s is removed", name)
+	}
+	return err
+}
+
+// newConsoleObjectMeta return the metadata shared by console objects, labels of minio are kept so that the
+// service handler enqueues the minio when the console service is changed
+func newConsoleObjectMeta(minio *crapiv1alpha1.Minio) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            getConsoleName(minio),
+		Namespace:       minio.GetNamespace(),
+		Labels:          getResourceLabels(minio),
+		Annotations:     getResourceAnnotations(minio, ""),
+		OwnerReferences: getResourceOwnerReference(minio),
+	}
+}
+
+func getConsoleSelector(minio *crapiv1alpha1.Minio) map[string]string {
+	return getComponentSelector(crconfig.MinioConsoleLabel, minio)
+}
+
+func newConsoleDeployment(minio *crapiv1alpha1.Minio) *appsv1.Deployment {
+	console := minio.Spec.Console
+	env := []apicorev1.EnvVar{
+		{Name: "CONSOLE_MINIO_SERVER", Value: fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", getInternalServiceName(minio), minio.GetNamespace(), getMinioPorts(minio).s3)},
+		consoleSecretEnvVar("CONSOLE_PBKDF_PASSPHRASE", minio, consolePassphraseKey),
+		consoleSecretEnvVar("CONSOLE_PBKDF_SALT", minio, consoleSaltKey),
+	}
+	if oidc := console.OIDC; oidc != nil {
+		env = append(env,
+			apicorev1.EnvVar{Name: "CONSOLE_IDP_URL", Value: oidc.ConfigURL},
+			apicorev1.EnvVar{Name: "CONSOLE_IDP_CLIENT_ID", Value: oidc.ClientID},
+			apicorev1.EnvVar{Name: "CONSOLE_IDP_SECRET", ValueFrom: &apicorev1.EnvVarSource{SecretKeyRef: oidc.ClientSecret.DeepCopy()}},
+		)
+		if len(oidc.Scopes) > 0 {
+			env = append(env, apicorev1.EnvVar{Name: "CONSOLE_IDP_SCOPES", Value: strings.Join(oidc.Scopes, ",")})
+		}
+		if oidc.CallbackURL != "" {
+			env = append(env, apicorev1.EnvVar{Name: "CONSOLE_IDP_CALLBACK", Value: oidc.CallbackURL})
+		}
+	}
+	if console.LDAP {
+		env = append(env, apicorev1.EnvVar{Name: "CONSOLE_LDAP_ENABLED", Value: "on"})
+	}
+
+	replicas := console.Replicas
+	deployment := &appsv1.Deployment{
+		ObjectMeta: newConsoleObjectMeta(minio),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: getConsoleSelector(minio)},
+			Template: apicorev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getConsoleSelector(minio)},
+				Spec: apicorev1.PodSpec{
+					Containers: []apicorev1.Container{
+						{
+							Name:  "console",
+							Image: console.Image,
+							Args:  []string{"server", fmt.Sprintf("--port=%d", console.Port)},
+							Ports: []apicorev1.ContainerPort{{Name: "http", ContainerPort: console.Port, Protocol: apicorev1.ProtocolTCP}},
+							Env:   env,
+							ReadinessProbe: &apicorev1.Probe{
+								ProbeHandler: apicorev1.ProbeHandler{
+									TCPSocket: &apicorev1.TCPSocketAction{Port: intstr.FromInt(int(console.Port))},
+								},
+								PeriodSeconds: 10,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	deployment.Annotations[crconfig.MinioSpecHash] = specHash(deployment.Spec)
+	return deployment
+}
+
+func newConsoleService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
+	return &apicorev1.Service{
+		ObjectMeta: newConsoleObjectMeta(minio),
+		Spec: apicorev1.ServiceSpec{
+			Ports: []apicorev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       minio.Spec.Console.Port,
+					TargetPort: intstr.FromInt(int(minio.Spec.Console.Port)),
+				},
+			},
+			Selector: getConsoleSelector(minio),
+			Type:     minio.Spec.Console.ServiceType,
+		},
+	}
+}
+
+func newConsoleIngress(minio *crapiv1alpha1.Minio) *networkingv1.Ingress {
+	spec := minio.Spec.Console.Ingress
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: newConsoleObjectMeta(minio),
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: spec.Host,
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								{
+									Path:     "/",
+									PathType: &pathType,
+									Backend: networkingv1.IngressBackend{
+										Service: &networkingv1.IngressServiceBackend{
+											Name: getConsoleName(minio),
+											Port: networkingv1.ServiceBackendPort{Number: minio.Spec.Console.Port},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if spec.IngressClassName != "" {
+		className := spec.IngressClassName
+		ingress.Spec.IngressClassName = &className
+	}
+	if spec.TLSSecret != "" {
+		ingress.Spec.TLS = []networkingv1.IngressTLS{{Hosts: []string{spec.Host}, SecretName: spec.TLSSecret}}
+	}
+	for key, value := range spec.Annotations {
+		ingress.Annotations[key] = value
+	}
+	ingress.Annotations[crconfig.MinioSpecHash] = specHash(struct {
+		Annotations map[string]string
+		Spec        networkingv1.IngressSpec
+	}{spec.Annotations, ingress.Spec})
+	return ingress
+}
+
+// consoleSecretEnvVar return env var whose value is read from the console secret
+func consoleSecretEnvVar(name string, minio *crapiv1alpha1.Minio, key string) apicorev1.EnvVar {
+	return apicorev1.EnvVar{
+		Name: name,
+		ValueFrom: &apicorev1.EnvVarSource{
+			SecretKeyRef: &apicorev1.SecretKeySelector{
+				LocalObjectReference: apicorev1.LocalObjectReference{Name: getConsoleName(minio)},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// specHash return a short hash of the desired spec, objects are updated only when it is changed so that fields
+// defaulted by kubernetes do not cause endless updates
+func specHash(spec interface{}) string {
+	data, _ := json.Marshal(spec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func ignoreNotFound(err error) error {
+	if k8serror.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+func getConsoleName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-console"
+}
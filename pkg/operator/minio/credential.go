@@ -0,0 +1,269 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// keys of the credential secret
+const (
+	credentialAccessKey         = "accessKey"
+	credentialSecretKey         = "secretKey"
+	credentialPreviousAccessKey = "previousAccessKey"
+	credentialPreviousSecretKey = "previousSecretKey"
+)
+
+// credentialHash return a short hash of credential, it is used to find members running with stale credential
+func credentialHash(credential crapiv1alpha1.Credential) string {
+	sum := sha256.Sum256([]byte(credential.AccessKey + "\x00" + credential.SecretKey))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// resolveCredential fill spec.credential from the secret referenced by credential.secretName of v1beta1, which is
+// kept in an annotation when the object is read as v1alpha1. the credential is only changed in memory, minio is
+// written back by patchAnnotations and UpdateStatus which never persist its spec
+func (o *operator) resolveCredential(minio *crapiv1alpha1.Minio) error {
+	name, ok := minio.GetAnnotations()[crapiv1alpha1.CredentialSecretAnnotation]
+	if !ok {
+		return nil
+	}
+	secret, err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return fmt.Errorf("secret %s/%s not found", minio.GetNamespace(), name)
+		}
+		return err
+	}
+	credential := crapiv1alpha1.Credential{AccessKey: string(secret.Data[credentialAccessKey]), SecretKey: string(secret.Data[credentialSecretKey])}
+	if credential.AccessKey == "" || credential.SecretKey == "" {
+		return fmt.Errorf("secret %s/%s must have %s and %s", minio.GetNamespace(), name, credentialAccessKey, credentialSecretKey)
+	}
+	minio.Spec.Credential = credential
+	return nil
+}
+
+// syncCredentialSecret make sure the credential secret holds spec.credential, when credential is changed
+// the credential accepted by the running cluster is kept as previous credential until the rotation is finished.
+// it returns the credentials the operator should try, the desired one comes first
+func (o *operator) syncCredentialSecret(minio *crapiv1alpha1.Minio) ([]crapiv1alpha1.Credential, error) {
+	desired := minio.Spec.Credential
+	secret, err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Get(context.TODO(), getCredentialSecretName(minio), metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return nil, err
+		}
+		if _, err = o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Create(context.TODO(), newCredentialSecret(minio), metav1.CreateOptions{}); err != nil {
+			return nil, err
+		}
+		return []crapiv1alpha1.Credential{desired}, nil
+	}
+
+	current := crapiv1alpha1.Credential{AccessKey: string(secret.Data[credentialAccessKey]), SecretKey: string(secret.Data[credentialSecretKey])}
+	previous := crapiv1alpha1.Credential{AccessKey: string(secret.Data[credentialPreviousAccessKey]), SecretKey: string(secret.Data[credentialPreviousSecretKey])}
+	if current == desired {
+		if previous.AccessKey == "" {
+			return []crapiv1alpha1.Credential{desired}, nil
+		}
+		return []crapiv1alpha1.Credential{desired, previous}, nil
+	}
+
+	// credential is changed, keep the one which is accepted by the cluster as previous credential.
+	// if a rotation is already in progress the previous credential is still the accepted one
+	secretCopy := secret.DeepCopy()
+	if secretCopy.Data == nil {
+		secretCopy.Data = map[string][]byte{}
+	}
+	if previous.AccessKey == "" {
+		previous = current
+		secretCopy.Data[credentialPreviousAccessKey] = []byte(current.AccessKey)
+		secretCopy.Data[credentialPreviousSecretKey] = []byte(current.SecretKey)
+	}
+	secretCopy.Data[credentialAccessKey] = []byte(desired.AccessKey)
+	secretCopy.Data[credentialSecretKey] = []byte(desired.SecretKey)
+	if _, err = o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Update(context.TODO(), secretCopy, metav1.UpdateOptions{}); err != nil {
+		return nil, err
+	}
+	o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonCredentialRotating, "Credential secret %s is updated, members will be restarted with the new credential", secretCopy.GetName())
+	return []crapiv1alpha1.Credential{desired, previous}, nil
+}
+
+// restartStaleMembers delete all members which are running with a stale credential or ports, they are recreated by
+// syncPods. minio requires all members of a cluster share the same root credential and endpoints, so stale members are
+// restarted together instead of one by one. it returns true if some members are restarting
+func (o *operator) restartStaleMembers(minio *crapiv1alpha1.Minio) (bool, error) {
+	desiredHash := credentialHash(minio.Spec.Credential)
+	desiredPorts := getMinioPorts(minio).String()
+	var (
+		stale        []*apicorev1.Pod
+		portsChanged bool
+	)
+	for index := 0; index < int(minio.Spec.Replicas); index++ {
+		pod, err := o.podLister.Pods(minio.GetNamespace()).Get(getPodName(index, minio))
+		if err != nil {
+			if k8serror.IsNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if pod.GetDeletionTimestamp() != nil {
+			// member is restarting
+			return true, nil
+		}
+		podHash, ok := pod.GetAnnotations()[crconfig.MinioCredentialHash]
+		if !ok {
+			// pods created before credential secret is introduced are running with the credential recorded in status
+			podHash = minio.Status.CredentialHash
+		}
+		podPorts, ok := pod.GetAnnotations()[crconfig.MinioPorts]
+		if !ok {
+			// pods created before ports are configurable listen on the default ports
+			podPorts = minioPorts{s3: 9000, console: 9001}.String()
+		}
+		switch {
+		case podPorts != desiredPorts:
+			portsChanged = true
+			stale = append(stale, pod)
+		case podHash != "" && podHash != desiredHash:
+			stale = append(stale, pod)
+		}
+	}
+	if len(stale) == 0 {
+		return false, nil
+	}
+	if portsChanged {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonPortsChanged, "Restarting %d members to apply the new ports %s", len(stale), desiredPorts)
+	} else {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonCredentialRotating, "Restarting %d members to apply the new credential", len(stale))
+	}
+	for _, pod := range stale {
+		if err := o.kubeClientSet.CoreV1().Pods(pod.GetNamespace()).Delete(context.TODO(), pod.GetName(), metav1.DeleteOptions{}); err != nil && !k8serror.IsNotFound(err) {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// finishCredentialRotation record the credential accepted by cluster into status, and drop the previous credential
+// from secret once the desired one is accepted
+func (o *operator) finishCredentialRotation(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential) error {
+	acceptedHash := credentialHash(accepted)
+	if accepted != minio.Spec.Credential {
+		// cluster still runs with the previous credential
+		minio.Status.CredentialHash = acceptedHash
+		return nil
+	}
+	if minio.Status.CredentialHash != "" && minio.Status.CredentialHash != acceptedHash {
+		now := metav1.Now()
+		minio.Status.CredentialRotationTime = &now
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonCredentialRotated, "Cluster accepted the new credential")
+	}
+	minio.Status.CredentialHash = acceptedHash
+
+	secret, err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Get(context.TODO(), getCredentialSecretName(minio), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	if _, ok := secret.Data[credentialPreviousAccessKey]; !ok {
+		return nil
+	}
+	secretCopy := secret.DeepCopy()
+	delete(secretCopy.Data, credentialPreviousAccessKey)
+	delete(secretCopy.Data, credentialPreviousSecretKey)
+	_, err = o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Update(context.TODO(), secretCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// isCredentialRejected return true if minio rejected the request because of credential
+func isCredentialRejected(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case "InvalidAccessKeyId", "SignatureDoesNotMatch", "AccessDenied":
+		return true
+	}
+	return false
+}
+
+func newCredentialSecret(minio *crapiv1alpha1.Minio) *apicorev1.Secret {
+	return &apicorev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getCredentialSecretName(minio),
+			Namespace:       minio.GetNamespace(),
+			Labels:          getResourceLabels(minio),
+			Annotations:     getResourceAnnotations(minio, ""),
+			OwnerReferences: getResourceOwnerReference(minio),
+		},
+		Type: apicorev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			credentialAccessKey: []byte(minio.Spec.Credential.AccessKey),
+			credentialSecretKey: []byte(minio.Spec.Credential.SecretKey),
+		},
+	}
+}
+
+// credentialEnvVar return env var whose value is read from the credential secret
+func credentialEnvVar(name string, minio *crapiv1alpha1.Minio, key string) apicorev1.EnvVar {
+	return apicorev1.EnvVar{
+		Name: name,
+		ValueFrom: &apicorev1.EnvVarSource{
+			SecretKeyRef: &apicorev1.SecretKeySelector{
+				LocalObjectReference: apicorev1.LocalObjectReference{Name: getCredentialSecretName(minio)},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+func credentialString(credential crapiv1alpha1.Credential) string {
+	return fmt.Sprintf("%s(%s)", credential.AccessKey, credentialHash(credential))
+}
+
+// NewApplicationClient return a minio-go client of the given minio, the root credential is read from its credential secret.
+// while a rotation is in progress the previous credential is used if the cluster has not accepted the new one yet
+func NewApplicationClient(ctx context.Context, kubeClientSet kubernetes.Interface, minioobject *crapiv1alpha1.Minio) (*minio.Client, error) {
+	secret, err := kubeClientSet.CoreV1().Secrets(minioobject.GetNamespace()).Get(ctx, getCredentialSecretName(minioobject), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	candidates := []crapiv1alpha1.Credential{{AccessKey: string(secret.Data[credentialAccessKey]), SecretKey: string(secret.Data[credentialSecretKey])}}
+	if previous, ok := secret.Data[credentialPreviousAccessKey]; ok {
+		candidates = append(candidates, crapiv1alpha1.Credential{AccessKey: string(previous), SecretKey: string(secret.Data[credentialPreviousSecretKey])})
+	}
+	var lastErr error
+	for _, credential := range candidates {
+		minioClient, err := newMinioClient(getMinioEndpoint(minioobject), credential)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = minioClient.ListBuckets(ctx); err != nil {
+			if isCredentialRejected(err) {
+				lastErr = err
+				continue
+			}
+			return nil, err
+		}
+		return minioClient, nil
+	}
+	return nil, fmt.Errorf("all credentials are rejected: %v", lastErr)
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apicorev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// maxErasureSetSize is the largest erasure set minio builds, every member of minio has one drive
+const maxErasureSetSize = 16
+
+// syncPodDisruptionBudget keep a pdb which allows as many voluntary evictions as an erasure set survives without
+// losing write quorum, it is recomputed when replicas or storage classes are changed. a single member is not
+// erasure coded, so it has no pdb
+func (o *operator) syncPodDisruptionBudget(minio *crapiv1alpha1.Minio) error {
+	pdbs := o.kubeClientSet.PolicyV1().PodDisruptionBudgets(minio.GetNamespace())
+	pdb, err := pdbs.Get(context.TODO(), minio.GetName(), metav1.GetOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if minio.Spec.Replicas <= 1 {
+		if !exists {
+			return nil
+		}
+		return ignoreNotFound(pdbs.Delete(context.TODO(), pdb.GetName(), metav1.DeleteOptions{}))
+	}
+	desired := newPodDisruptionBudget(minio)
+	if !exists {
+		_, err = pdbs.Create(context.TODO(), desired, metav1.CreateOptions{})
+	} else if pdb.GetAnnotations()[crconfig.MinioSpecHash] != desired.GetAnnotations()[crconfig.MinioSpecHash] {
+		pdbCopy := pdb.DeepCopy()
+		pdbCopy.Labels = desired.Labels
+		pdbCopy.Annotations = desired.Annotations
+		pdbCopy.Spec = desired.Spec
+		_, err = pdbs.Update(context.TODO(), pdbCopy, metav1.UpdateOptions{})
+	} else {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonDisruptionBudgetSynced, "PodDisruptionBudget %s allows %s unavailable members",
+		desired.GetName(), desired.Spec.MaxUnavailable.String())
+	return nil
+}
+
+func newPodDisruptionBudget(minio *crapiv1alpha1.Minio) *policyv1.PodDisruptionBudget {
+	maxUnavailable := intstr.FromInt(maxUnavailableMembers(minio))
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            minio.GetName(),
+			Namespace:       minio.GetNamespace(),
+			Labels:          getResourceLabels(minio),
+			Annotations:     getResourceAnnotations(minio, ""),
+			OwnerReferences: getResourceOwnerReference(minio),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector:       &metav1.LabelSelector{MatchLabels: getResourceLabels(minio)},
+		},
+	}
+	pdb.Annotations[crconfig.MinioSpecHash] = specHash(pdb.Spec)
+	return pdb
+}
+
+// maxUnavailableMembers return how many members can be down without losing write quorum. members of one erasure
+// set may be evicted together, so the budget of the whole cluster is the budget of one set. no member may be evicted
+// if replicas can not be split into erasure sets
+func maxUnavailableMembers(minio *crapiv1alpha1.Minio) int {
+	setSize, ok := erasureSetSize(int(minio.Spec.Replicas))
+	if !ok {
+		return 0
+	}
+	parity := erasureParity(minio, setSize)
+	// write quorum needs one more drive when data and parity are equal
+	if parity*2 == setSize {
+		return parity - 1
+	}
+	return parity
+}
+
+// erasureSetSize return the size of erasure sets of the given drives, at most 16 drives form a single set, more
+// drives are split into sets of the largest divisor which is not greater than 16. it returns false if there is no
+// such divisor, minio refuses to start with those drives
+func erasureSetSize(drives int) (int, bool) {
+	if drives <= maxErasureSetSize {
+		return drives, true
+	}
+	for size := maxErasureSetSize; size > 1; size-- {
+		if drives%size == 0 {
+			return size, true
+		}
+	}
+	return 0, false
+}
+
+// validateErasureSets return error if minio can not split the members into erasure sets, every member has one drive
+func validateErasureSets(minio *crapiv1alpha1.Minio) error {
+	if _, ok := erasureSetSize(int(minio.Spec.Replicas)); !ok {
+		return fmt.Errorf("%d replicas can not be split into erasure sets of 2 to %d members", minio.Spec.Replicas, maxErasureSetSize)
+	}
+	return nil
+}
+
+// erasureParity return the lowest parity objects may be written with, it is the parity of the STANDARD or the RRS
+// storage class configured by settings or env, or the default parity of minio for the set size
+func erasureParity(minio *crapiv1alpha1.Minio, setSize int) int {
+	parity := defaultErasureParity(setSize)
+	standard, rrs := -1, -1
+	for _, env := range settingsEnv(minio) {
+		switch env.Name {
+		case "MINIO_STORAGE_CLASS_STANDARD":
+			standard = parseStorageClassParity(env.Value)
+		case "MINIO_STORAGE_CLASS_RRS":
+			rrs = parseStorageClassParity(env.Value)
+		}
+	}
+	if standard >= 0 {
+		parity = standard
+	}
+	if rrs >= 0 && rrs < parity {
+		parity = rrs
+	}
+	return parity
+}
+
+// defaultErasureParity is the parity minio uses when the STANDARD storage class is not set
+func defaultErasureParity(setSize int) int {
+	switch {
+	case setSize == 1:
+		return 0
+	case setSize <= 3:
+		return 1
+	case setSize <= 5:
+		return 2
+	case setSize <= 7:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// parseStorageClassParity parse EC:N, it returns -1 if value is not a storage class
+func parseStorageClassParity(value string) int {
+	if !strings.HasPrefix(value, "EC:") {
+		return -1
+	}
+	parity, err := strconv.Atoi(strings.TrimPrefix(value, "EC:"))
+	if err != nil || parity < 0 {
+		return -1
+	}
+	return parity
+}
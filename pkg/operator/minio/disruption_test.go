@@ -0,0 +1,63 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"testing"
+
+	apicorev1 "k8s.io/api/core/v1"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+func TestMaxUnavailableMembers(t *testing.T) {
+	tests := []struct {
+		name     string
+		replicas int32
+		settings *crapiv1alpha1.ServerSettings
+		env      []apicorev1.EnvVar
+		want     int
+	}{
+		// data and parity are equal, write quorum needs one more drive
+		{name: "2 replicas", replicas: 2, want: 0},
+		{name: "3 replicas", replicas: 3, want: 1},
+		{name: "4 replicas", replicas: 4, want: 1},
+		{name: "16 replicas", replicas: 16, want: 4},
+		// split into two sets of 16
+		{name: "32 replicas", replicas: 32, want: 4},
+		{name: "16 replicas with EC:2", replicas: 16, settings: &crapiv1alpha1.ServerSettings{StorageClassStandard: "EC:2"}, want: 2},
+		{name: "16 replicas with EC:8", replicas: 16, settings: &crapiv1alpha1.ServerSettings{StorageClassStandard: "EC:8"}, want: 7},
+		{name: "4 replicas with RRS EC:1", replicas: 4, settings: &crapiv1alpha1.ServerSettings{StorageClassRRS: "EC:1"}, want: 1},
+		{name: "32 replicas with env over settings", replicas: 32, settings: &crapiv1alpha1.ServerSettings{StorageClassStandard: "EC:2"},
+			env: []apicorev1.EnvVar{{Name: "MINIO_STORAGE_CLASS_STANDARD", Value: "EC:6"}}, want: 6},
+		// no set size divides 17, minio can not start
+		{name: "17 replicas", replicas: 17, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			minio := &crapiv1alpha1.Minio{Spec: crapiv1alpha1.MinioSpec{Replicas: tt.replicas, Settings: tt.settings, Env: tt.env}}
+			if got := maxUnavailableMembers(minio); got != tt.want {
+				t.Errorf("maxUnavailableMembers() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateErasureSets(t *testing.T) {
+	for replicas, valid := range map[int32]bool{1: true, 7: true, 16: true, 17: false, 18: true, 19: false, 37: false, 48: true} {
+		minio := &crapiv1alpha1.Minio{Spec: crapiv1alpha1.MinioSpec{Replicas: replicas}}
+		if err := validateErasureSets(minio); (err == nil) != valid {
+			t.Errorf("validateErasureSets(%d replicas) = %v, want valid %v", replicas, err, valid)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/sse"
+	appsv1 "k8s.io/api/apps/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+const (
+	kesPort = 7373
+	// kesKeyStatusPath is the admin api which checks that a key of KMS can encrypt and decrypt
+	kesKeyStatusPath = "/minio/admin/v3/kms/key/status"
+	// mount paths of the KES client certificate and ca in minio pods
+	kesClientCertPath = "/etc/minio/kes"
+	kesCAPath         = "/etc/minio/kes-ca"
+	// mount paths of the server certificate and config in KES pods
+	kesServerCertPath = "/etc/kes/tls"
+	kesConfigPath     = "/etc/kes/config"
+	kesConfigFile     = "server-config.yaml"
+	// kesIdentityEnv is the env of KES pods holding the identity of minio, it is referenced by the config of KES
+	kesIdentityEnv = "MINIO_KES_IDENTITY"
+)
+
+// kmsKeyStatus is the response of the kms key status api
+type kmsKeyStatus struct {
+	KeyID         string `json:"key-id"`
+	EncryptionErr string `json:"encryption-error,omitempty"`
+	DecryptionErr string `json:"decryption-error,omitempty"`
+}
+
+// encryptionEnv return the environment variables connecting minio to KES
+func encryptionEnv(minio *crapiv1alpha1.Minio) []apicorev1.EnvVar {
+	encryption := minio.Spec.Encryption
+	if encryption == nil {
+		return nil
+	}
+	env := []apicorev1.EnvVar{
+		{Name: "MINIO_KMS_KES_ENDPOINT", Value: getKESEndpoint(minio)},
+		{Name: "MINIO_KMS_KES_KEY_NAME", Value: encryption.KeyName},
+		{Name: "MINIO_KMS_KES_CERT_FILE", Value: path.Join(kesClientCertPath, apicorev1.TLSCertKey)},
+		{Name: "MINIO_KMS_KES_KEY_FILE", Value: path.Join(kesClientCertPath, apicorev1.TLSPrivateKeyKey)},
+	}
+	if encryption.CASecret != "" {
+		env = append(env, apicorev1.EnvVar{Name: "MINIO_KMS_KES_CAPATH", Value: path.Join(kesCAPath, "ca.crt")})
+	}
+	return env
+}
+
+// encryptionVolumes return the volumes and mounts of the KES client certificate and ca in minio pods
+func encryptionVolumes(minio *crapiv1alpha1.Minio) ([]apicorev1.Volume, []apicorev1.VolumeMount) {
+	encryption := minio.Spec.Encryption
+	if encryption == nil {
+		return nil, nil
+	}
+	volumes := []apicorev1.Volume{secretVolume("kes-client", encryption.ClientCertSecret)}
+	mounts := []apicorev1.VolumeMount{{Name: "kes-client", MountPath: kesClientCertPath, ReadOnly: true}}
+	if encryption.CASecret != "" {
+		volumes = append(volumes, secretVolume("kes-ca", encryption.CASecret))
+		mounts = append(mounts, apicorev1.VolumeMount{Name: "kes-ca", MountPath: kesCAPath, ReadOnly: true})
+	}
+	return volumes, mounts
+}
+
+// syncKES make the KES deployment and service match spec.encryption.kes, they are removed when minio uses an
+// external KES or no encryption. the client certificate is checked in both cases because minio pods mount it
+func (o *operator) syncKES(minio *crapiv1alpha1.Minio) error {
+	if minio.Spec.Encryption == nil {
+		return o.removeKES(minio)
+	}
+	secret, err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Get(context.TODO(), minio.Spec.Encryption.ClientCertSecret, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get kes client certificate failed: %v", err)
+	}
+	identity, err := kesIdentity(secret.Data[apicorev1.TLSCertKey])
+	if err != nil {
+		return fmt.Errorf("secret %s: %v", secret.GetName(), err)
+	}
+	if minio.Spec.Encryption.KES == nil {
+		return o.removeKES(minio)
+	}
+	changed, err := o.syncDeployment(newKESDeployment(minio, identity))
+	if err != nil {
+		return fmt.Errorf("sync kes deployment failed: %v", err)
+	}
+	if _, err = o.syncService(minio, newKESService(minio)); err != nil {
+		return fmt.Errorf("sync kes service failed: %v", err)
+	}
+	if changed {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonKESSynced, "KES %s is synced", getKESName(minio))
+	}
+	return nil
+}
+
+// removeKES delete the KES deployment and service
+func (o *operator) removeKES(minio *crapiv1alpha1.Minio) error {
+	name := getKESName(minio)
+	removed, err := o.removeComponent(minio.GetNamespace(), name, nil)
+	if removed {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonKESRemoved, "KES %s is removed", name)
+	}
+	return err
+}
+
+// syncBucketEncryption set the default encryption of spec.encryption.buckets, a bucket is only changed after the
+// key it uses is verified by minio. the result is recorded in the EncryptionReady condition
+func (o *operator) syncBucketEncryption(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential) error {
+	encryption := minio.Spec.Encryption
+	if encryption == nil {
+		meta.RemoveStatusCondition(&minio.Status.Conditions, crapiv1alpha1.MinioConditionEncryptionReady)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+
+	keyErrors := map[string]error{}
+	checkKey := func(keyID string) error {
+		if err, ok := keyErrors[keyID]; ok {
+			return err
+		}
+		err := checkKMSKey(ctx, minio, accepted, keyID)
+		keyErrors[keyID] = err
+		return err
+	}
+	// SSE-S3 derives keys from the default key, so it is verified even if no bucket uses SSE-KMS
+	if err := checkKey(encryption.KeyName); err != nil {
+		o.setCondition(minio, crapiv1alpha1.MinioConditionEncryptionReady, metav1.ConditionFalse, EncryptionReasonKeyUnavailable, err.Error(), false)
+		return nil
+	}
+	client, err := newMinioClient(getMinioEndpoint(minio), accepted)
+	if err != nil {
+		return err
+	}
+	for _, bucket := range encryption.Buckets {
+		keyID := encryption.KeyName
+		desired := sse.NewConfigurationSSES3()
+		if bucket.Algorithm == crapiv1alpha1.BucketEncryptionSSEKMS {
+			keyID = bucket.KeyID
+			desired = sse.NewConfigurationSSEKMS(keyID)
+		}
+		if err = checkKey(keyID); err != nil {
+			o.setCondition(minio, crapiv1alpha1.MinioConditionEncryptionReady, metav1.ConditionFalse, EncryptionReasonKeyUnavailable,
+				fmt.Sprintf("Bucket %s is not encrypted: %v", bucket.Bucket, err), false)
+			return nil
+		}
+		if err = setBucketEncryption(ctx, client, bucket.Bucket, desired); err != nil {
+			o.setCondition(minio, crapiv1alpha1.MinioConditionEncryptionReady, metav1.ConditionFalse, EncryptionReasonBucketFailed,
+				fmt.Sprintf("Set encryption of bucket %s failed: %v", bucket.Bucket, err), false)
+			return nil
+		}
+	}
+	o.setCondition(minio, crapiv1alpha1.MinioConditionEncryptionReady, metav1.ConditionTrue, EncryptionReasonReady,
+		fmt.Sprintf("Key %s is available, %d buckets are encrypted", encryption.KeyName, len(encryption.Buckets)), false)
+	return nil
+}
+
+// checkKMSKey ask minio to encrypt and decrypt with the key, it returns an error if the key does not exist or
+// KES is not reachable
+func checkKMSKey(ctx context.Context, minio *crapiv1alpha1.Minio, credential crapiv1alpha1.Credential, keyID string) error {
+	status := &kmsKeyStatus{}
+	apiURL := fmt.Sprintf("http://%s%s?key-id=%s", getMinioEndpoint(minio), kesKeyStatusPath, url.QueryEscape(keyID))
+	if err := callAdminAPI(ctx, apiURL, credential, status); err != nil {
+		return fmt.Errorf("check key %s failed: %v", keyID, err)
+	}
+	if status.EncryptionErr != "" {
+		return fmt.Errorf("key %s can not encrypt: %s", keyID, status.EncryptionErr)
+	}
+	if status.DecryptionErr != "" {
+		return fmt.Errorf("key %s can not decrypt: %s", keyID, status.DecryptionErr)
+	}
+	return nil
+}
+
+// setBucketEncryption set the default encryption of bucket if it is different from desired
+func setBucketEncryption(ctx context.Context, client *minio.Client, bucket string, desired *sse.Configuration) error {
+	current, err := client.GetBucketEncryption(ctx, bucket)
+	if err != nil && minio.ToErrorResponse(err).Code != "ServerSideEncryptionConfigurationNotFoundError" {
+		return err
+	}
+	if err == nil && len(current.Rules) == 1 && current.Rules[0].Apply == desired.Rules[0].Apply {
+		return nil
+	}
+	return client.SetBucketEncryption(ctx, bucket, desired)
+}
+
+// kesIdentity return the identity of a client certificate in KES, it is the sha256 of the public key
+func kesIdentity(certPEM []byte) (string, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return "", fmt.Errorf("%s is not a pem encoded certificate", apicorev1.TLSCertKey)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func getKESSelector(minio *crapiv1alpha1.Minio) map[string]string {
+	return getComponentSelector(crconfig.MinioKESLabel, minio)
+}
+
+func newKESObjectMeta(minio *crapiv1alpha1.Minio) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            getKESName(minio),
+		Namespace:       minio.GetNamespace(),
+		Labels:          getResourceLabels(minio),
+		Annotations:     getResourceAnnotations(minio, ""),
+		OwnerReferences: getResourceOwnerReference(minio),
+	}
+}
+
+func newKESDeployment(minio *crapiv1alpha1.Minio, identity string) *appsv1.Deployment {
+	kes := minio.Spec.Encryption.KES
+	replicas := kes.Replicas
+	deployment := &appsv1.Deployment{
+		ObjectMeta: newKESObjectMeta(minio),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: getKESSelector(minio)},
+			Template: apicorev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getKESSelector(minio)},
+				Spec: apicorev1.PodSpec{
+					Volumes: []apicorev1.Volume{
+						secretVolume("tls", kes.ServerCertSecret),
+						secretVolume("config", kes.ConfigSecret),
+					},
+					Containers: []apicorev1.Container{
+						{
+							Name:  "kes",
+							Image: kes.Image,
+							Args: []string{
+								"server",
+								"--config=" + path.Join(kesConfigPath, kesConfigFile),
+								"--key=" + path.Join(kesServerCertPath, apicorev1.TLSPrivateKeyKey),
+								"--cert=" + path.Join(kesServerCertPath, apicorev1.TLSCertKey),
+								// minio is authorized by the identity in the config, its certificate may be self signed
+								"--auth=off",
+							},
+							Ports: []apicorev1.ContainerPort{{Name: "https", ContainerPort: kesPort, Protocol: apicorev1.ProtocolTCP}},
+							Env:   []apicorev1.EnvVar{{Name: kesIdentityEnv, Value: identity}},
+							VolumeMounts: []apicorev1.VolumeMount{
+								{Name: "tls", MountPath: kesServerCertPath, ReadOnly: true},
+								{Name: "config", MountPath: kesConfigPath, ReadOnly: true},
+							},
+							ReadinessProbe: &apicorev1.Probe{
+								ProbeHandler: apicorev1.ProbeHandler{
+									TCPSocket: &apicorev1.TCPSocketAction{Port: intstr.FromInt(kesPort)},
+								},
+								PeriodSeconds: 10,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	deployment.Annotations[crconfig.MinioSpecHash] = specHash(deployment.Spec)
+	return deployment
+}
+
+func newKESService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
+	return &apicorev1.Service{
+		ObjectMeta: newKESObjectMeta(minio),
+		Spec: apicorev1.ServiceSpec{
+			Ports: []apicorev1.ServicePort{
+				{Name: "https", Port: kesPort, TargetPort: intstr.FromInt(kesPort)},
+			},
+			Selector: getKESSelector(minio),
+			Type:     apicorev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+func secretVolume(name, secret string) apicorev1.Volume {
+	return apicorev1.Volume{
+		Name:         name,
+		VolumeSource: apicorev1.VolumeSource{Secret: &apicorev1.SecretVolumeSource{SecretName: secret}},
+	}
+}
+
+// getKESEndpoint return the external endpoint or the service of the KES managed by operator
+func getKESEndpoint(minio *crapiv1alpha1.Minio) string {
+	if minio.Spec.Encryption.Endpoint != "" {
+		return minio.Spec.Encryption.Endpoint
+	}
+	return fmt.Sprintf("https://%s.%s.svc:%d", getKESName(minio), minio.GetNamespace(), kesPort)
+}
+
+func getKESName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-kes"
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+const (
+	// ldapBindDNKey and ldapBindPasswordKey are the keys of spec.identity.ldap.lookupBindSecret
+	ldapBindDNKey       = "bindDN"
+	ldapBindPasswordKey = "bindPassword"
+)
+
+// identityEnv return the environment variables of spec.identity, secrets are referenced instead of copied
+// so that they are never written into the pod spec
+func identityEnv(minio *crapiv1alpha1.Minio) []apicorev1.EnvVar {
+	var env []apicorev1.EnvVar
+	add := func(name, value string) {
+		if value != "" {
+			env = append(env, apicorev1.EnvVar{Name: name, Value: value})
+		}
+	}
+	addSecret := func(name, secret, key string) {
+		env = append(env, apicorev1.EnvVar{Name: name, ValueFrom: &apicorev1.EnvVarSource{
+			SecretKeyRef: &apicorev1.SecretKeySelector{LocalObjectReference: apicorev1.LocalObjectReference{Name: secret}, Key: key},
+		}})
+	}
+	identity := minio.Spec.Identity
+	if identity == nil {
+		return nil
+	}
+	if openid := identity.OpenID; openid != nil {
+		add("MINIO_IDENTITY_OPENID_CONFIG_URL", openid.ConfigURL)
+		add("MINIO_IDENTITY_OPENID_CLIENT_ID", openid.ClientID)
+		addSecret("MINIO_IDENTITY_OPENID_CLIENT_SECRET", openid.ClientSecret.Name, openid.ClientSecret.Key)
+		add("MINIO_IDENTITY_OPENID_CLAIM_NAME", openid.ClaimName)
+		add("MINIO_IDENTITY_OPENID_SCOPES", strings.Join(openid.Scopes, ","))
+		add("MINIO_IDENTITY_OPENID_REDIRECT_URI", openid.RedirectURI)
+	}
+	if ldap := identity.LDAP; ldap != nil {
+		add("MINIO_IDENTITY_LDAP_SERVER_ADDR", ldap.ServerAddr)
+		addSecret("MINIO_IDENTITY_LDAP_LOOKUP_BIND_DN", ldap.LookupBindSecret, ldapBindDNKey)
+		addSecret("MINIO_IDENTITY_LDAP_LOOKUP_BIND_PASSWORD", ldap.LookupBindSecret, ldapBindPasswordKey)
+		add("MINIO_IDENTITY_LDAP_USER_DN_SEARCH_BASE_DN", ldap.UserDNSearchBaseDN)
+		add("MINIO_IDENTITY_LDAP_USER_DN_SEARCH_FILTER", ldap.UserDNSearchFilter)
+		add("MINIO_IDENTITY_LDAP_GROUP_SEARCH_BASE_DN", ldap.GroupSearchBaseDN)
+		add("MINIO_IDENTITY_LDAP_GROUP_SEARCH_FILTER", ldap.GroupSearchFilter)
+		if tls := ldap.TLS; tls != nil {
+			if tls.InsecureSkipVerify {
+				add("MINIO_IDENTITY_LDAP_TLS_SKIP_VERIFY", onOff(true))
+			}
+			if tls.StartTLS {
+				add("MINIO_IDENTITY_LDAP_SERVER_STARTTLS", onOff(true))
+			}
+			if tls.Insecure {
+				add("MINIO_IDENTITY_LDAP_SERVER_INSECURE", onOff(true))
+			}
+		}
+	}
+	return env
+}
+
+// validateIdentity check spec.identity and the secrets it references, minio refuses to start with an invalid
+// identity provider so pods must not be created or restarted until it is fixed
+func (o *operator) validateIdentity(minio *crapiv1alpha1.Minio) error {
+	identity := minio.Spec.Identity
+	if identity == nil {
+		return nil
+	}
+	if openid := identity.OpenID; openid != nil {
+		if openid.ConfigURL == "" || openid.ClientID == "" {
+			return fmt.Errorf("openid configURL and clientID are required")
+		}
+		if u, err := url.Parse(openid.ConfigURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("openid configURL %q is not a http(s) url", openid.ConfigURL)
+		}
+		if openid.RedirectURI != "" {
+			if u, err := url.Parse(openid.RedirectURI); err != nil || u.Host == "" {
+				return fmt.Errorf("openid redirectURI %q is not a valid url", openid.RedirectURI)
+			}
+		}
+		if err := o.checkSecretKeys(minio.GetNamespace(), openid.ClientSecret.Name, openid.ClientSecret.Key); err != nil {
+			return fmt.Errorf("openid clientSecret: %v", err)
+		}
+	}
+	if ldap := identity.LDAP; ldap != nil {
+		if _, port, err := net.SplitHostPort(ldap.ServerAddr); err != nil || port == "" {
+			return fmt.Errorf("ldap serverAddr %q is not host:port", ldap.ServerAddr)
+		}
+		if ldap.UserDNSearchBaseDN == "" || !strings.Contains(ldap.UserDNSearchFilter, "%s") {
+			return fmt.Errorf("ldap userDNSearchBaseDN is required and userDNSearchFilter must contain %%s")
+		}
+		if (ldap.GroupSearchBaseDN == "") != (ldap.GroupSearchFilter == "") {
+			return fmt.Errorf("ldap groupSearchBaseDN and groupSearchFilter must be set together")
+		}
+		if ldap.GroupSearchFilter != "" && !strings.Contains(ldap.GroupSearchFilter, "%d") && !strings.Contains(ldap.GroupSearchFilter, "%s") {
+			return fmt.Errorf("ldap groupSearchFilter must contain %%d or %%s")
+		}
+		if tls := ldap.TLS; tls != nil && tls.StartTLS && tls.Insecure {
+			return fmt.Errorf("ldap tls startTLS and insecure are exclusive")
+		}
+		if err := o.checkSecretKeys(minio.GetNamespace(), ldap.LookupBindSecret, ldapBindDNKey, ldapBindPasswordKey); err != nil {
+			return fmt.Errorf("ldap lookupBindSecret: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkSecretKeys return an error if the secret is missing or any of keys is empty
+func (o *operator) checkSecretKeys(namespace, name string, keys ...string) error {
+	if name == "" {
+		return fmt.Errorf("secret name is required")
+	}
+	secret, err := o.kubeClientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return fmt.Errorf("secret %s/%s not found", namespace, name)
+		}
+		return err
+	}
+	for _, key := range keys {
+		if len(secret.Data[key]) == 0 {
+			return fmt.Errorf("secret %s/%s has no key %q", namespace, name, key)
+		}
+	}
+	return nil
+}
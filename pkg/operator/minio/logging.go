@@ -0,0 +1,317 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+const (
+	logCollectorPort = 8080
+	// logCollectorTarget is the audit target of the collector, it can not be used by spec.logging.audit
+	logCollectorTarget = "collector"
+	logCollectorPath   = "/data"
+	// keys of spec.logging.*.kafka.saslSecret
+	kafkaUsernameKey = "username"
+	kafkaPasswordKey = "password"
+)
+
+// loggingEnv return the environment variables of spec.logging, each target is rendered with its name as suffix
+func loggingEnv(minio *crapiv1alpha1.Minio) []apicorev1.EnvVar {
+	logging := minio.Spec.Logging
+	if logging == nil {
+		return nil
+	}
+	var env []apicorev1.EnvVar
+	for _, target := range logging.Audit {
+		env = append(env, logTargetEnv("MINIO_AUDIT", target)...)
+	}
+	if logging.Collector != nil {
+		env = append(env, logTargetEnv("MINIO_AUDIT", crapiv1alpha1.LogTarget{
+			Name:    logCollectorTarget,
+			Webhook: &crapiv1alpha1.WebhookTarget{Endpoint: getLogCollectorEndpoint(minio)},
+		})...)
+	}
+	for _, target := range logging.Server {
+		env = append(env, logTargetEnv("MINIO_LOGGER", target)...)
+	}
+	return env
+}
+
+// logTargetEnv render a target as the env of minio, e.g. MINIO_AUDIT_WEBHOOK_ENDPOINT_<NAME>
+func logTargetEnv(prefix string, target crapiv1alpha1.LogTarget) []apicorev1.EnvVar {
+	suffix := "_" + strings.ToUpper(strings.ReplaceAll(target.Name, "-", "_"))
+	var env []apicorev1.EnvVar
+	add := func(name, value string) {
+		env = append(env, apicorev1.EnvVar{Name: prefix + name + suffix, Value: value})
+	}
+	addSecret := func(name string, selector apicorev1.SecretKeySelector) {
+		env = append(env, apicorev1.EnvVar{Name: prefix + name + suffix, ValueFrom: &apicorev1.EnvVarSource{SecretKeyRef: &selector}})
+	}
+	if webhook := target.Webhook; webhook != nil {
+		add("_WEBHOOK_ENABLE", onOff(true))
+		add("_WEBHOOK_ENDPOINT", webhook.Endpoint)
+		if webhook.AuthToken != nil {
+			addSecret("_WEBHOOK_AUTH_TOKEN", *webhook.AuthToken)
+		}
+	}
+	if kafka := target.Kafka; kafka != nil {
+		add("_KAFKA_ENABLE", onOff(true))
+		add("_KAFKA_BROKERS", strings.Join(kafka.Brokers, ","))
+		add("_KAFKA_TOPIC", kafka.Topic)
+		if kafka.TLS {
+			add("_KAFKA_TLS", onOff(true))
+		}
+		if kafka.SASLSecret != "" {
+			secret := apicorev1.LocalObjectReference{Name: kafka.SASLSecret}
+			add("_KAFKA_SASL", onOff(true))
+			addSecret("_KAFKA_SASL_USERNAME", apicorev1.SecretKeySelector{LocalObjectReference: secret, Key: kafkaUsernameKey})
+			addSecret("_KAFKA_SASL_PASSWORD", apicorev1.SecretKeySelector{LocalObjectReference: secret, Key: kafkaPasswordKey})
+			if kafka.SASLMechanism != "" {
+				add("_KAFKA_SASL_MECHANISM", kafka.SASLMechanism)
+			}
+		}
+	}
+	return env
+}
+
+// validateLogging check spec.logging and the secrets it references before they are rendered into pods
+func (o *operator) validateLogging(minio *crapiv1alpha1.Minio) error {
+	logging := minio.Spec.Logging
+	if logging == nil {
+		return nil
+	}
+	if err := o.validateLogTargets(minio, "audit", logging.Audit, true); err != nil {
+		return err
+	}
+	if err := o.validateLogTargets(minio, "server", logging.Server, false); err != nil {
+		return err
+	}
+	if collector := logging.Collector; collector != nil {
+		if _, err := resource.ParseQuantity(collector.Size); err != nil {
+			return fmt.Errorf("collector size %q is invalid: %v", collector.Size, err)
+		}
+		for _, target := range logging.Audit {
+			if strings.EqualFold(target.Name, logCollectorTarget) {
+				return fmt.Errorf("audit target %s is reserved by the collector", logCollectorTarget)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *operator) validateLogTargets(minio *crapiv1alpha1.Minio, kind string, targets []crapiv1alpha1.LogTarget, kafkaAllowed bool) error {
+	names := map[string]bool{}
+	for _, target := range targets {
+		// names are the suffix of env, so they are compared as minio sees them
+		id := strings.ToUpper(strings.ReplaceAll(target.Name, "-", "_"))
+		if id == "" || names[id] {
+			return fmt.Errorf("%s target name %q is empty or duplicated", kind, target.Name)
+		}
+		names[id] = true
+		if (target.Webhook == nil) == (target.Kafka == nil) {
+			return fmt.Errorf("%s target %s must have exactly one of webhook and kafka", kind, target.Name)
+		}
+		if webhook := target.Webhook; webhook != nil {
+			if u, err := url.Parse(webhook.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("%s target %s: endpoint %q is not a http(s) url", kind, target.Name, webhook.Endpoint)
+			}
+			if webhook.AuthToken != nil {
+				if err := o.checkSecretKeys(minio.GetNamespace(), webhook.AuthToken.Name, webhook.AuthToken.Key); err != nil {
+					return fmt.Errorf("%s target %s: authToken: %v", kind, target.Name, err)
+				}
+			}
+		}
+		if kafka := target.Kafka; kafka != nil {
+			if !kafkaAllowed {
+				return fmt.Errorf("%s target %s: minio does not support kafka for %s logs", kind, target.Name, kind)
+			}
+			if len(kafka.Brokers) == 0 || kafka.Topic == "" {
+				return fmt.Errorf("%s target %s: kafka brokers and topic are required", kind, target.Name)
+			}
+			for _, broker := range kafka.Brokers {
+				if _, _, err := net.SplitHostPort(broker); err != nil {
+					return fmt.Errorf("%s target %s: broker %q is not host:port", kind, target.Name, broker)
+				}
+			}
+			if kafka.SASLSecret != "" {
+				if err := o.checkSecretKeys(minio.GetNamespace(), kafka.SASLSecret, kafkaUsernameKey, kafkaPasswordKey); err != nil {
+					return fmt.Errorf("%s target %s: saslSecret: %v", kind, target.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// syncLogCollector make the collector deployment, service and pvc match spec.logging.collector. the deployment
+// and service are removed when the collector is disabled, the pvc is kept until minio is deleted
+func (o *operator) syncLogCollector(minio *crapiv1alpha1.Minio) error {
+	if minio.Spec.Logging == nil || minio.Spec.Logging.Collector == nil {
+		return o.removeLogCollector(minio)
+	}
+	if err := o.syncLogCollectorClaim(minio); err != nil {
+		return fmt.Errorf("sync log collector pvc failed: %v", err)
+	}
+	changed, err := o.syncDeployment(newLogCollectorDeployment(minio))
+	if err != nil {
+		return fmt.Errorf("sync log collector deployment failed: %v", err)
+	}
+	if _, err = o.syncService(minio, newLogCollectorService(minio)); err != nil {
+		return fmt.Errorf("sync log collector service failed: %v", err)
+	}
+	if changed {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonLogCollectorSynced, "Log collector %s is synced", getLogCollectorName(minio))
+	}
+	return nil
+}
+
+// syncLogCollectorClaim create the pvc of collector, it is never updated because most fields of pvc are immutable
+func (o *operator) syncLogCollectorClaim(minio *crapiv1alpha1.Minio) error {
+	claims := o.kubeClientSet.CoreV1().PersistentVolumeClaims(minio.GetNamespace())
+	_, err := claims.Get(context.TODO(), getLogCollectorName(minio), metav1.GetOptions{})
+	if ignoreNotFound(err) != nil || err == nil {
+		return err
+	}
+	claim, err := newLogCollectorClaim(minio)
+	if err != nil {
+		return err
+	}
+	_, err = claims.Create(context.TODO(), claim, metav1.CreateOptions{})
+	return err
+}
+
+func newLogCollectorClaim(minio *crapiv1alpha1.Minio) (*apicorev1.PersistentVolumeClaim, error) {
+	collector := minio.Spec.Logging.Collector
+	size, err := resource.ParseQuantity(collector.Size)
+	if err != nil {
+		return nil, fmt.Errorf("collector size %q is invalid: %v", collector.Size, err)
+	}
+	return &apicorev1.PersistentVolumeClaim{
+		ObjectMeta: newLogCollectorObjectMeta(minio),
+		Spec: apicorev1.PersistentVolumeClaimSpec{
+			AccessModes:      []apicorev1.PersistentVolumeAccessMode{apicorev1.ReadWriteOnce},
+			StorageClassName: collector.StorageClassName,
+			Resources: apicorev1.ResourceRequirements{
+				Requests: apicorev1.ResourceList{apicorev1.ResourceStorage: size},
+			},
+		},
+	}, nil
+}
+
+// removeLogCollector delete the collector deployment and service, the pvc holding audit events is kept
+func (o *operator) removeLogCollector(minio *crapiv1alpha1.Minio) error {
+	name := getLogCollectorName(minio)
+	removed, err := o.removeComponent(minio.GetNamespace(), name, nil)
+	if removed {
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonLogCollectorRemoved, "Log collector %s is removed, pvc %s is kept", name, name)
+	}
+	return err
+}
+
+func newLogCollectorObjectMeta(minio *crapiv1alpha1.Minio) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            getLogCollectorName(minio),
+		Namespace:       minio.GetNamespace(),
+		Labels:          getResourceLabels(minio),
+		Annotations:     getResourceAnnotations(minio, ""),
+		OwnerReferences: getResourceOwnerReference(minio),
+	}
+}
+
+func getLogCollectorSelector(minio *crapiv1alpha1.Minio) map[string]string {
+	return getComponentSelector(crconfig.MinioLogCollectorLabel, minio)
+}
+
+// newLogCollectorDeployment return a fluent-bit which receives audit events with the http input and appends
+// them as json lines to audit.log in the pvc
+func newLogCollectorDeployment(minio *crapiv1alpha1.Minio) *appsv1.Deployment {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: newLogCollectorObjectMeta(minio),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{MatchLabels: getLogCollectorSelector(minio)},
+			// the pvc is ReadWriteOnce, the old pod must release it first
+			Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+			Template: apicorev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getLogCollectorSelector(minio)},
+				Spec: apicorev1.PodSpec{
+					Volumes: []apicorev1.Volume{
+						{
+							Name: "data",
+							VolumeSource: apicorev1.VolumeSource{
+								PersistentVolumeClaim: &apicorev1.PersistentVolumeClaimVolumeSource{ClaimName: getLogCollectorName(minio)},
+							},
+						},
+					},
+					Containers: []apicorev1.Container{
+						{
+							Name:  "collector",
+							Image: minio.Spec.Logging.Collector.Image,
+							Command: []string{
+								"/fluent-bit/bin/fluent-bit",
+								"-i", "http", "-p", fmt.Sprintf("port=%d", logCollectorPort),
+								"-o", "file", "-m", "*", "-p", "path=" + logCollectorPath, "-p", "file=audit.log", "-p", "format=plain",
+							},
+							Ports:        []apicorev1.ContainerPort{{Name: "http", ContainerPort: logCollectorPort, Protocol: apicorev1.ProtocolTCP}},
+							VolumeMounts: []apicorev1.VolumeMount{{Name: "data", MountPath: logCollectorPath}},
+							ReadinessProbe: &apicorev1.Probe{
+								ProbeHandler: apicorev1.ProbeHandler{
+									TCPSocket: &apicorev1.TCPSocketAction{Port: intstr.FromInt(logCollectorPort)},
+								},
+								PeriodSeconds: 10,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	deployment.Annotations[crconfig.MinioSpecHash] = specHash(deployment.Spec)
+	return deployment
+}
+
+func newLogCollectorService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
+	return &apicorev1.Service{
+		ObjectMeta: newLogCollectorObjectMeta(minio),
+		Spec: apicorev1.ServiceSpec{
+			Ports: []apicorev1.ServicePort{
+				{Name: "http", Port: logCollectorPort, TargetPort: intstr.FromInt(logCollectorPort)},
+			},
+			Selector: getLogCollectorSelector(minio),
+			Type:     apicorev1.ServiceTypeClusterIP,
+		},
+	}
+}
+
+func getLogCollectorEndpoint(minio *crapiv1alpha1.Minio) string {
+	return fmt.Sprintf("http://%s.%s.svc:%d/", getLogCollectorName(minio), minio.GetNamespace(), logCollectorPort)
+}
+
+func getLogCollectorName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-log-collector"
+}
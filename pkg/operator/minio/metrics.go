@@ -0,0 +1,61 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const metricsNamespace = "minio_operator"
+
+// operatorMetrics represent the metrics of minio clusters managed by operator
+type operatorMetrics struct {
+	podReady      *prometheus.GaugeVec
+	buckets       *prometheus.GaugeVec
+	clusterHealth *prometheus.GaugeVec
+}
+
+func newOperatorMetrics(reg prometheus.Registerer) *operatorMetrics {
+	m := &operatorMetrics{
+		podReady: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Name: "pod_ready",
+			Help: "Whether the minio pod is ready (1) or not (0)",
+		}, []string{"namespace", "name", "pod"}),
+		buckets: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Name: "buckets",
+			Help: "Number of buckets in the minio cluster",
+		}, []string{"namespace", "name"}),
+		clusterHealth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricsNamespace, Name: "cluster_healthy",
+			Help: "Result of the minio cluster health probe, 1 means the cluster has write quorum",
+		}, []string{"namespace", "name"}),
+	}
+	if reg != nil {
+		reg.MustRegister(m.podReady, m.buckets, m.clusterHealth)
+	}
+	return m
+}
+
+// forget remove all series of the given minio
+func (m *operatorMetrics) forget(namespace, name string) {
+	m.podReady.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+	m.buckets.DeleteLabelValues(namespace, name)
+	m.clusterHealth.DeleteLabelValues(namespace, name)
+}
+
+func boolToFloat64(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,298 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+const (
+	prometheusAuthPublic = "public"
+	// clusterMetricsPath is the metrics api of the whole cluster, every member serves it
+	clusterMetricsPath = "/minio/v2/metrics/cluster"
+	// keys of the prometheus secret
+	prometheusTokenKey        = "token"
+	prometheusScrapeConfigKey = "scrape-config.yaml"
+	// prometheusTokenExpiry is the same as `mc admin prometheus generate`, the token is replaced when the credential
+	// is rotated
+	prometheusTokenExpiry = 100 * 365 * 24 * time.Hour
+)
+
+var (
+	monitoringGroupVersion = schema.GroupVersion{Group: "monitoring.coreos.com", Version: "v1"}
+	serviceMonitorResource = monitoringGroupVersion.WithResource("servicemonitors")
+	podMonitorResource     = monitoringGroupVersion.WithResource("podmonitors")
+)
+
+// syncMonitoring keep the prometheus secret and monitors matching spec.monitoring and the credential accepted by
+// the cluster, everything is removed when spec.monitoring is not set
+func (o *operator) syncMonitoring(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential) error {
+	if minio.Spec.Monitoring == nil {
+		return o.removeMonitoring(minio)
+	}
+	public := isPrometheusPublic(minio)
+	if err := o.syncPrometheusSecret(minio, accepted, public); err != nil {
+		return fmt.Errorf("sync prometheus secret failed: %v", err)
+	}
+	monitors := []struct {
+		resource schema.GroupVersionResource
+		kind     string
+		enabled  bool
+	}{
+		{serviceMonitorResource, "ServiceMonitor", minio.Spec.Monitoring.ServiceMonitor},
+		{podMonitorResource, "PodMonitor", minio.Spec.Monitoring.PodMonitor},
+	}
+	for _, monitor := range monitors {
+		if !monitor.enabled {
+			if err := o.deleteMonitor(minio, monitor.resource); err != nil {
+				return err
+			}
+			continue
+		}
+		installed, err := o.isMonitorInstalled(monitor.kind)
+		if err != nil {
+			return err
+		}
+		if !installed {
+			klog.V(2).Infof("%s/%s skip %s, the crd of prometheus operator is not installed", minio.GetNamespace(), minio.GetName(), monitor.kind)
+			continue
+		}
+		if err = o.syncMonitor(minio, monitor.resource, newMonitor(minio, monitor.kind, public)); err != nil {
+			return fmt.Errorf("sync %s failed: %v", monitor.kind, err)
+		}
+	}
+	return nil
+}
+
+// syncPrometheusSecret create or update the secret holding the bearer token and the scrape configuration, the token
+// is only generated again when the credential or auth type is changed
+func (o *operator) syncPrometheusSecret(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential, public bool) error {
+	hash := specHash(struct {
+		Credential string
+		Public     bool
+		Target     string
+		Interval   string
+	}{credentialHash(accepted), public, getMetricsTarget(minio), minio.Spec.Monitoring.Interval})
+	secrets := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace())
+	secret, err := secrets.Get(context.TODO(), getPrometheusSecretName(minio), metav1.GetOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+	if exists && secret.GetAnnotations()[crconfig.MinioSpecHash] == hash {
+		return nil
+	}
+
+	var token string
+	if !public {
+		if token, err = newPrometheusToken(accepted, time.Now()); err != nil {
+			return err
+		}
+	}
+	scrapeConfig, err := newScrapeConfig(minio, token)
+	if err != nil {
+		return err
+	}
+	desired := &apicorev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getPrometheusSecretName(minio),
+			Namespace:       minio.GetNamespace(),
+			Labels:          getResourceLabels(minio),
+			Annotations:     getResourceAnnotations(minio, ""),
+			OwnerReferences: getResourceOwnerReference(minio),
+		},
+		Type: apicorev1.SecretTypeOpaque,
+		Data: map[string][]byte{prometheusScrapeConfigKey: scrapeConfig},
+	}
+	if token != "" {
+		desired.Data[prometheusTokenKey] = []byte(token)
+	}
+	desired.Annotations[crconfig.MinioSpecHash] = hash
+	if !exists {
+		_, err = secrets.Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err
+	}
+	secretCopy := secret.DeepCopy()
+	secretCopy.Annotations = desired.Annotations
+	secretCopy.Data = desired.Data
+	_, err = secrets.Update(context.TODO(), secretCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// syncMonitor create the monitor or replace its spec if it is changed
+func (o *operator) syncMonitor(minio *crapiv1alpha1.Minio, resource schema.GroupVersionResource, desired *unstructured.Unstructured) error {
+	client := o.dynamicClient.Resource(resource).Namespace(minio.GetNamespace())
+	monitor, err := client.Get(context.TODO(), desired.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return err
+		}
+		_, err = client.Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err
+	}
+	if monitor.GetAnnotations()[crconfig.MinioSpecHash] == desired.GetAnnotations()[crconfig.MinioSpecHash] {
+		return nil
+	}
+	desired.SetResourceVersion(monitor.GetResourceVersion())
+	_, err = client.Update(context.TODO(), desired, metav1.UpdateOptions{})
+	return err
+}
+
+// removeMonitoring delete the secret and monitors, the secret tells whether there is anything to remove
+func (o *operator) removeMonitoring(minio *crapiv1alpha1.Minio) error {
+	secrets := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace())
+	if _, err := secrets.Get(context.TODO(), getPrometheusSecretName(minio), metav1.GetOptions{}); err != nil {
+		return ignoreNotFound(err)
+	}
+	for _, resource := range []schema.GroupVersionResource{serviceMonitorResource, podMonitorResource} {
+		if err := o.deleteMonitor(minio, resource); err != nil {
+			return err
+		}
+	}
+	return ignoreNotFound(secrets.Delete(context.TODO(), getPrometheusSecretName(minio), metav1.DeleteOptions{}))
+}
+
+// deleteMonitor delete the monitor of minio, it is not an error if the monitor or its crd does not exist
+func (o *operator) deleteMonitor(minio *crapiv1alpha1.Minio, resource schema.GroupVersionResource) error {
+	err := o.dynamicClient.Resource(resource).Namespace(minio.GetNamespace()).Delete(context.TODO(), getPrometheusSecretName(minio), metav1.DeleteOptions{})
+	return ignoreNotFound(err)
+}
+
+// isMonitorInstalled return true if the kind of prometheus operator is served by the apiserver
+func (o *operator) isMonitorInstalled(kind string) (bool, error) {
+	resources, err := o.kubeClientSet.Discovery().ServerResourcesForGroupVersion(monitoringGroupVersion.String())
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Kind == kind {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// newMonitor return a ServiceMonitor selecting the internal service or a PodMonitor selecting minio pods, both
+// scrape the http port of minio
+func newMonitor(minio *crapiv1alpha1.Minio, kind string, public bool) *unstructured.Unstructured {
+	endpoint := map[string]interface{}{
+		"port":     "http",
+		"path":     clusterMetricsPath,
+		"scheme":   "http",
+		"interval": minio.Spec.Monitoring.Interval,
+	}
+	if !public {
+		endpoint["bearerTokenSecret"] = map[string]interface{}{
+			"name": getPrometheusSecretName(minio),
+			"key":  prometheusTokenKey,
+		}
+	}
+	spec := map[string]interface{}{
+		"namespaceSelector": map[string]interface{}{"matchNames": []interface{}{minio.GetNamespace()}},
+	}
+	if kind == "ServiceMonitor" {
+		spec["selector"] = map[string]interface{}{"matchLabels": map[string]interface{}{crconfig.MinioInternalServiceLabel: minio.GetName()}}
+		spec["endpoints"] = []interface{}{endpoint}
+	} else {
+		spec["selector"] = map[string]interface{}{"matchLabels": map[string]interface{}{crconfig.MinioAppNameLabel: minio.GetName()}}
+		spec["podMetricsEndpoints"] = []interface{}{endpoint}
+	}
+
+	monitor := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	monitor.SetAPIVersion(monitoringGroupVersion.String())
+	monitor.SetKind(kind)
+	monitor.SetName(getPrometheusSecretName(minio))
+	monitor.SetNamespace(minio.GetNamespace())
+	labels := getResourceLabels(minio)
+	for key, value := range minio.Spec.Monitoring.Labels {
+		labels[key] = value
+	}
+	monitor.SetLabels(labels)
+	annotations := getResourceAnnotations(minio, "")
+	annotations[crconfig.MinioSpecHash] = specHash(monitor.Object)
+	monitor.SetAnnotations(annotations)
+	monitor.SetOwnerReferences(getResourceOwnerReference(minio))
+	return monitor
+}
+
+// newScrapeConfig return a scrape config of prometheus for users who do not run prometheus operator
+func newScrapeConfig(minio *crapiv1alpha1.Minio, token string) ([]byte, error) {
+	job := map[string]interface{}{
+		"job_name":        fmt.Sprintf("minio-%s-%s", minio.GetNamespace(), minio.GetName()),
+		"metrics_path":    clusterMetricsPath,
+		"scheme":          "http",
+		"scrape_interval": minio.Spec.Monitoring.Interval,
+		"static_configs":  []interface{}{map[string]interface{}{"targets": []string{getMetricsTarget(minio)}}},
+	}
+	if token != "" {
+		job["bearer_token"] = token
+	}
+	return yaml.Marshal([]interface{}{job})
+}
+
+// newPrometheusToken return the jwt which minio accepts for the metrics api, it is signed with HS512 by the secret
+// key like `mc admin prometheus generate`
+func newPrometheusToken(credential crapiv1alpha1.Credential, now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "HS512", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"exp": now.Add(prometheusTokenExpiry).Unix(),
+		"sub": credential.AccessKey,
+		"iss": "prometheus",
+	})
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	mac := hmac.New(sha512.New, []byte(credential.SecretKey))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + encoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// isPrometheusPublic return true if minio serves metrics without token, it reads MINIO_PROMETHEUS_AUTH_TYPE from the final env list
+// so that spec.env wins over spec.settings, which wins over spec.monitoring
+func isPrometheusPublic(minio *crapiv1alpha1.Minio) bool {
+	return settingsEnvValue(minio, "MINIO_PROMETHEUS_AUTH_TYPE") == prometheusAuthPublic
+}
+
+// getMetricsTarget return the address of the internal service which prometheus scrapes
+func getMetricsTarget(minio *crapiv1alpha1.Minio) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local:%d", getInternalServiceName(minio), minio.GetNamespace(), getMinioPorts(minio).s3)
+}
+
+func getPrometheusSecretName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-prometheus"
+}
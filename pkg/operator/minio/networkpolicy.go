@@ -0,0 +1,119 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+
+	apicorev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// syncNetworkPolicies make the network policies of minio members and console match spec.networkPolicy, they are
+// removed when spec.networkPolicy is not set
+func (o *operator) syncNetworkPolicies(minio *crapiv1alpha1.Minio) error {
+	desired := newNetworkPolicies(minio)
+	policies := o.kubeClientSet.NetworkingV1().NetworkPolicies(minio.GetNamespace())
+	for _, name := range []string{minio.GetName(), getConsoleName(minio)} {
+		want, ok := desired[name]
+		policy, err := policies.Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil && !k8serror.IsNotFound(err) {
+			return err
+		}
+		exists := err == nil
+		action := "synced"
+		switch {
+		case !ok && !exists:
+			continue
+		case !ok:
+			action = "removed"
+			err = ignoreNotFound(policies.Delete(context.TODO(), name, metav1.DeleteOptions{}))
+		case !exists:
+			_, err = policies.Create(context.TODO(), want, metav1.CreateOptions{})
+		case policy.GetAnnotations()[crconfig.MinioSpecHash] != want.GetAnnotations()[crconfig.MinioSpecHash]:
+			policyCopy := policy.DeepCopy()
+			policyCopy.Labels = want.Labels
+			policyCopy.Annotations = want.Annotations
+			policyCopy.Spec = want.Spec
+			_, err = policies.Update(context.TODO(), policyCopy, metav1.UpdateOptions{})
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonNetworkPolicySynced, "NetworkPolicy %s is %s", name, action)
+	}
+	return nil
+}
+
+// newNetworkPolicies return the desired policies keyed by name. members accept the s3 port from each other, the
+// console, the site replication job, the operator and spec.networkPolicy.api, and the console port from spec.networkPolicy.console
+func newNetworkPolicies(minio *crapiv1alpha1.Minio) map[string]*networkingv1.NetworkPolicy {
+	spec := minio.Spec.NetworkPolicy
+	if spec == nil {
+		return nil
+	}
+	ports := getMinioPorts(minio)
+	s3Peers := []networkingv1.NetworkPolicyPeer{
+		{PodSelector: &metav1.LabelSelector{MatchLabels: getResourceLabels(minio)}},
+		{PodSelector: &metav1.LabelSelector{MatchLabels: getConsoleSelector(minio)}},
+		{PodSelector: &metav1.LabelSelector{MatchLabels: getSiteReplicationSelector(minio)}},
+	}
+	s3Peers = append(s3Peers, spec.Operator...)
+	s3Peers = append(s3Peers, spec.API...)
+	policies := map[string]*networkingv1.NetworkPolicy{
+		minio.GetName(): newNetworkPolicy(minio, minio.GetName(), getResourceLabels(minio), []networkingv1.NetworkPolicyIngressRule{
+			{From: s3Peers, Ports: networkPolicyPorts(ports.s3)},
+			// an empty list of peers allows everyone
+			{From: spec.Console, Ports: networkPolicyPorts(ports.console)},
+		}),
+	}
+	if minio.Spec.Console != nil && len(spec.Console) > 0 {
+		policies[getConsoleName(minio)] = newNetworkPolicy(minio, getConsoleName(minio), getConsoleSelector(minio), []networkingv1.NetworkPolicyIngressRule{
+			{From: spec.Console, Ports: networkPolicyPorts(minio.Spec.Console.Port)},
+		})
+	}
+	return policies
+}
+
+func newNetworkPolicy(minio *crapiv1alpha1.Minio, name string, selector map[string]string, rules []networkingv1.NetworkPolicyIngressRule) *networkingv1.NetworkPolicy {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       minio.GetNamespace(),
+			Labels:          getResourceLabels(minio),
+			Annotations:     getResourceAnnotations(minio, ""),
+			OwnerReferences: getResourceOwnerReference(minio),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: selector},
+			Ingress:     rules,
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+		},
+	}
+	policy.Annotations[crconfig.MinioSpecHash] = specHash(policy.Spec)
+	return policy
+}
+
+func networkPolicyPorts(port int32) []networkingv1.NetworkPolicyPort {
+	protocol := apicorev1.ProtocolTCP
+	target := intstr.FromInt(int(port))
+	return []networkingv1.NetworkPolicyPort{{Protocol: &protocol, Port: &target}}
+}
@@ -15,16 +15,17 @@ package minio
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
-	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	apicorev1 "k8s.io/api/core/v1"
 	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -44,28 +45,33 @@ import (
 type operator struct {
 	minioClient   crclientset.Interface
 	kubeClientSet kubernetes.Interface
+	dynamicClient dynamic.Interface
 	recorder      record.EventRecorder
 	minioLister   crlisterv1alpha1.MinioLister
 	reg           prometheus.Registerer
 	serviceLister listercorev1.ServiceLister
 	podLister     listercorev1.PodLister
 	nodeLister    listercorev1.NodeLister
+	metrics       *operatorMetrics
 }
 
-func NewOperator(kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, podLister listercorev1.PodLister, serviceLister listercorev1.ServiceLister, nodeLister listercorev1.NodeLister, minioLister crlisterv1alpha1.MinioLister, recorder record.EventRecorder, reg prometheus.Registerer) croperator.Operator {
+func NewOperator(kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, dynamicClient dynamic.Interface, podLister listercorev1.PodLister, serviceLister listercorev1.ServiceLister, nodeLister listercorev1.NodeLister, minioLister crlisterv1alpha1.MinioLister, recorder record.EventRecorder, reg prometheus.Registerer) croperator.Operator {
 	return &operator{
 		minioClient:   crClientSet,
 		minioLister:   minioLister,
+		recorder:      recorder,
 		reg:           reg,
 		kubeClientSet: kubeClientSet,
+		dynamicClient: dynamicClient,
 
 		podLister:     podLister,
 		serviceLister: serviceLister,
 		nodeLister:    nodeLister,
+		metrics:       newOperatorMetrics(reg),
 	}
 }
 
-func (o *operator) Reconcile(object interface{}) error {
+func (o *operator) Reconcile(object interface{}) (reconcileErr error) {
 	namespace, name, err := cache.SplitMetaNamespaceKey(object.(string))
 	if err != nil {
 		utilruntime.HandleError(fmt.Errorf("failed to get the namespace and name from key: %v : %v", object, err))
@@ -75,6 +81,7 @@ func (o *operator) Reconcile(object interface{}) error {
 
 	if minio, err := o.minioLister.Minios(namespace).Get(name); err != nil {
 		if k8serror.IsNotFound(err) {
+			o.metrics.forget(namespace, name)
 			return nil
 		}
 		return fmt.Errorf("%s/%s get minio failed %v", namespace, name, err)
@@ -82,9 +89,50 @@ func (o *operator) Reconcile(object interface{}) error {
 		minioCopy = minio.DeepCopy()
 	}
 
+	// a paused minio is left as it is until the annotation is removed, only its status is refreshed
+	if crapiv1alpha1.IsMinioPaused(minioCopy) {
+		return o.syncPaused(minioCopy)
+	}
+	o.markResumed(minioCopy)
+
+	// the referenced credential must be resolved before defaulter fills the default credential
+	if err = o.resolveCredential(minioCopy); err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonInvalidCredential, "Invalid credential secret: %v", err)
+		return fmt.Errorf("%s/%s resolve credential failed %v", namespace, name, err)
+	}
 	// defaulter
 	crapiv1alpha1.MinioDefaulter(minioCopy)
 
+	// status is updated at the end of a successful reconcile, members are still counted when it fails early so that
+	// the phase and the scale subresource are not stale
+	var statusUpdated bool
+	defer func() {
+		if reconcileErr == nil || statusUpdated {
+			return
+		}
+		if err := o.syncReplicaStatus(minioCopy); err != nil {
+			utilruntime.HandleError(fmt.Errorf("%s/%s count ready members failed %v", namespace, name, err))
+			return
+		}
+		if _, err := o.minioClient.MiniooperatorV1alpha1().Minios(namespace).UpdateStatus(context.TODO(), minioCopy, metav1.UpdateOptions{}); err != nil {
+			utilruntime.HandleError(fmt.Errorf("%s/%s update replica status failed %v", namespace, name, err))
+		}
+	}()
+
+	// replicas fix the endpoints of the pool, they are checked against the members before syncNodes limits them to
+	// the ready nodes
+	if err = validateErasureSets(minioCopy); err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonInvalidReplicas, "Invalid replicas: %v", err)
+		return fmt.Errorf("%s/%s validate replicas failed %v", namespace, name, err)
+	}
+	if err = o.validateReplicas(minioCopy); err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonScaleDownRefused, "Refused to scale down: %v", err)
+		return fmt.Errorf("%s/%s validate replicas failed %v", namespace, name, err)
+	}
+	if err = o.cleanupMembers(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s clean up members failed %v", namespace, name, err)
+	}
+
 	var nodes []string
 	// sync all nodes, this step is used to list all nodes , and upate minio. replicas according the number of nodes
 	// if only has one node in kubernetes cluster, then we set replicas of minio to 1
@@ -92,6 +140,16 @@ func (o *operator) Reconcile(object interface{}) error {
 		return fmt.Errorf("%s/%s sync node failed, err %v", namespace, name, err)
 	}
 
+	// identity providers and log targets are rendered into the pods, and logging also configures the collector,
+	// so they are checked before anything is synced
+	if err = o.validateIdentity(minioCopy); err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonInvalidIdentity, "Invalid identity provider: %v", err)
+		return fmt.Errorf("%s/%s validate identity failed %v", namespace, name, err)
+	}
+	if err = o.validateLogging(minioCopy); err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonInvalidLogging, "Invalid logging targets: %v", err)
+		return fmt.Errorf("%s/%s validate logging failed %v", namespace, name, err)
+	}
 	// sync Service
 	if _, err = o.syncInternalService(minioCopy); err != nil {
 		return fmt.Errorf("%s/%s sync service failed %s", namespace, name, err)
@@ -99,25 +157,74 @@ func (o *operator) Reconcile(object interface{}) error {
 	if _, err = o.syncExternalService(minioCopy); err != nil {
 		return fmt.Errorf("%s/%s sync service failed %s", namespace, name, err)
 	}
-	// sync pods
-	var shouldUpdate bool
-	_, shouldUpdate, err = o.syncPods(minioCopy, nodes)
-	if shouldUpdate {
-		preErr := err
-		if minioCopy, err = o.minioClient.MiniooperatorV1alpha1().Minios(namespace).Update(context.TODO(), minioCopy, metav1.UpdateOptions{}); err != nil {
-			err = errors.Wrapf(preErr, "update minio'annno failed: %v", err)
-		}
+	if err = o.syncPodDisruptionBudget(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s sync pod disruption budget failed %v", namespace, name, err)
+	}
+	if err = o.syncNetworkPolicies(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s sync network policies failed %v", namespace, name, err)
+	}
+	if err = o.syncConsole(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s sync console failed %v", namespace, name, err)
+	}
+	if err = o.syncKES(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s sync kes failed %v", namespace, name, err)
 	}
+	if err = o.syncLogCollector(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s sync log collector failed %v", namespace, name, err)
+	}
+	// sync credential, members running with a stale credential are restarted before pods are synced
+	candidates, err := o.syncCredentialSecret(minioCopy)
+	if err != nil {
+		return fmt.Errorf("%s/%s sync credential secret failed %v", namespace, name, err)
+	}
+	restarting, err := o.restartStaleMembers(minioCopy)
 	if err != nil {
+		return fmt.Errorf("%s/%s restart stale members failed %v", namespace, name, err)
+	}
+	if restarting {
+		return fmt.Errorf("%s/%s waiting for members to be restarted with the new credential or ports", namespace, name)
+	}
+	// sync pods
+	if _, err = o.syncPods(minioCopy, nodes); err != nil {
 		return err
 	}
-	if err = o.syncMinioApplication(minioCopy, 60*time.Second); err != nil {
+	accepted, err := o.syncMinioApplication(minioCopy, candidates, 60*time.Second)
+	if err != nil {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeWarning, EventReasonApplicationNotReady, "Minio application is not ready: %v", err)
 		return fmt.Errorf("Sync minio application failed: %v", err)
 	}
+	if err = o.finishCredentialRotation(minioCopy, accepted); err != nil {
+		return fmt.Errorf("%s/%s finish credential rotation failed %v", namespace, name, err)
+	}
+	if err = o.syncSiteReplication(minioCopy, accepted); err != nil {
+		return fmt.Errorf("%s/%s sync site replication failed %v", namespace, name, err)
+	}
+	if err = o.syncBucketEncryption(minioCopy, accepted); err != nil {
+		return fmt.Errorf("%s/%s sync bucket encryption failed %v", namespace, name, err)
+	}
+	if err = o.syncMonitoring(minioCopy, accepted); err != nil {
+		return fmt.Errorf("%s/%s sync monitoring failed %v", namespace, name, err)
+	}
+	previousStatus := minioCopy.Status.Inited
 	minioCopy.Status.Inited = "Ok"
+	if err = o.syncReplicaStatus(minioCopy); err != nil {
+		return fmt.Errorf("%s/%s count ready members failed %v", namespace, name, err)
+	}
 	if _, err = o.minioClient.MiniooperatorV1alpha1().Minios(namespace).UpdateStatus(context.TODO(), minioCopy, metav1.UpdateOptions{}); err != nil {
 		return fmt.Errorf("Update minio apps status failed %v", err)
 	}
+	statusUpdated = true
+	if previousStatus != minioCopy.Status.Inited {
+		o.recorder.Eventf(minioCopy, apicorev1.EventTypeNormal, EventReasonStatusChanged, "Status changed from %q to %q", previousStatus, minioCopy.Status.Inited)
+	}
+	// members are restarted one by one after the cluster is online, the reconcile is retried until all are updated
+	restarting, err = o.restartOutdatedMember(minioCopy)
+	if err != nil {
+		return fmt.Errorf("%s/%s restart members for settings failed %v", namespace, name, err)
+	}
+	if restarting {
+		return fmt.Errorf("%s/%s waiting for members to be restarted with the new settings", namespace, name)
+	}
 
 	return nil
 
@@ -139,27 +246,33 @@ func (o *operator) syncNodes(minio *crapiv1alpha1.Minio, nodes *[]string) error
 			}
 		}
 	}
-	if len(*nodes) == 1 {
+	limitReplicasToNodes(minio, *nodes)
+	return nil
+}
+
+// limitReplicasToNodes set replicas to 1 if there is only one ready node, running many members on one node is useless
+func limitReplicasToNodes(minio *crapiv1alpha1.Minio, nodes []string) {
+	if len(nodes) == 1 {
 		minio.Spec.Replicas = 1
 	}
-	return nil
 }
 
 // operator represent operator
-func (o *operator) syncPods(minio *crapiv1alpha1.Minio, allNodes []string) ([]*apicorev1.Pod, bool, error) {
+// the node picked for a new member is recorded in annotations of minio, so it is recreated on the same node
+func (o *operator) syncPods(minio *crapiv1alpha1.Minio, allNodes []string) ([]*apicorev1.Pod, error) {
 	nodeResPoll := make(map[int][]string, 64) //
 	nodeResPoll[0] = allNodes
 	podShoudCreate := []string{}
-	crIsUpdate := false
 	for index := 0; index < int(minio.Spec.Replicas); index++ {
 		pod, err := o.podLister.Pods(minio.GetNamespace()).Get(getPodName(index, minio))
 		if err != nil {
 			if !k8serror.IsNotFound(err) {
-				return nil, crIsUpdate, err
+				return nil, err
 			}
 			podShoudCreate = append(podShoudCreate, getPodName(index, minio))
 			continue
 		}
+		o.metrics.podReady.WithLabelValues(minio.GetNamespace(), minio.GetName(), pod.GetName()).Set(boolToFloat64(IsPodReady(pod)))
 		// if pod existed ,then update nodeinfo
 		nodeName, _ := pod.GetAnnotations()[crconfig.MinioAppLocation]
 		updateNodeAllocatedInfo(nodeResPoll, nodeName)
@@ -167,50 +280,75 @@ func (o *operator) syncPods(minio *crapiv1alpha1.Minio, allNodes []string) ([]*a
 	// create some pods if necessary
 	for _, podName := range podShoudCreate {
 		pickedNode := nodeNameForSchedulePod(podName, minio, nodeResPoll)
+		if pickedNode == "" {
+			o.recorder.Eventf(minio, apicorev1.EventTypeWarning, EventReasonNoNodeAvailable, "No ready node is available for pod %s", podName)
+			return nil, fmt.Errorf("no ready node is available for pod %s", podName)
+		}
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonNodeSelected, "Node %s is selected for pod %s", pickedNode, podName)
 		pod, err := o.kubeClientSet.CoreV1().Pods(minio.GetNamespace()).Create(context.TODO(), newPod(podName, minio, pickedNode), metav1.CreateOptions{})
 		if err != nil {
-			return nil, crIsUpdate, err
+			o.recorder.Eventf(minio, apicorev1.EventTypeWarning, EventReasonPodCreateFailed, "Create pod %s failed: %v", podName, err)
+			return nil, err
 		}
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonPodCreated, "Created pod %s on node %s", podName, pickedNode)
 		// here means schedule is validate
-		crIsUpdate = true
-		minio.Annotations[podName] = pickedNode
+		if err := o.patchAnnotations(minio, map[string]interface{}{podName: pickedNode}); err != nil {
+			return nil, fmt.Errorf("record node of pod %s failed: %v", podName, err)
+		}
 		if err := o.waitForPodReady(pod, 30*time.Second); err != nil {
-			return nil, crIsUpdate, err
+			o.recorder.Eventf(minio, apicorev1.EventTypeWarning, EventReasonPodNotReady, "Pod %s is not ready in %v: %v", podName, 30*time.Second, err)
+			return nil, err
 		}
 	}
-	return nil, crIsUpdate, nil
+	return nil, nil
 }
 
 // operator represent operator
 func (o *operator) syncInternalService(minio *crapiv1alpha1.Minio) (*apicorev1.Service, error) {
-	// if service is existed, then return nil
-	svc, err := o.serviceLister.Services(minio.GetNamespace()).Get(getInternalServiceName(minio))
-	if err == nil {
-		return svc, nil
-	}
-	// get service failed, buf not because sevice is not existed, for some other reasone
-	if !k8serror.IsNotFound(err) {
-		return nil, err
-	}
-	// service is not existed, then create new one
-	svc, err = o.kubeClientSet.CoreV1().Services(minio.GetNamespace()).Create(context.TODO(), newInternalService(minio), metav1.CreateOptions{})
-	return svc, err
+	return o.syncService(minio, newInternalService(minio))
 }
 
 // operator represent operator
 func (o *operator) syncExternalService(minio *crapiv1alpha1.Minio) (*apicorev1.Service, error) {
-	// if service is existed, then return nil
-	svc, err := o.serviceLister.Services(minio.GetNamespace()).Get(getExternalServiceName(minio))
-	if err == nil {
-		return svc, nil
-	}
+	return o.syncService(minio, newExternalService(minio))
+}
+
+// syncService create the service if it is not existed, and repair its ports, selector and labels if they are changed
+func (o *operator) syncService(minio *crapiv1alpha1.Minio, desired *apicorev1.Service) (*apicorev1.Service, error) {
+	svc, err := o.serviceLister.Services(minio.GetNamespace()).Get(desired.GetName())
 	// get service failed, buf not because sevice is not existed, for some other reasone
-	if !k8serror.IsNotFound(err) {
+	if err != nil && !k8serror.IsNotFound(err) {
 		return nil, err
 	}
 	// service is not existed, then create new one
-	svc, err = o.kubeClientSet.CoreV1().Services(minio.GetNamespace()).Create(context.TODO(), newExternalService(minio), metav1.CreateOptions{})
-	return svc, err
+	if err != nil {
+		svc, err = o.kubeClientSet.CoreV1().Services(minio.GetNamespace()).Create(context.TODO(), desired, metav1.CreateOptions{})
+		if err != nil {
+			o.recorder.Eventf(minio, apicorev1.EventTypeWarning, EventReasonServiceSyncFailed, "Create service %s failed: %v", desired.GetName(), err)
+			return nil, err
+		}
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonServiceCreated, "Created service %s", desired.GetName())
+		return svc, nil
+	}
+	if servicePortsEqual(svc.Spec.Ports, desired.Spec.Ports) && reflect.DeepEqual(svc.Spec.Selector, desired.Spec.Selector) && labelsContain(svc.GetLabels(), desired.GetLabels()) {
+		return svc, nil
+	}
+	// service is existed but drifted, keep allocated fields(clusterIP, nodePort) and repair the others
+	svcCopy := svc.DeepCopy()
+	svcCopy.Spec.Selector = desired.Spec.Selector
+	svcCopy.Spec.Ports = mergeServicePorts(svc.Spec.Ports, desired.Spec.Ports)
+	if svcCopy.Labels == nil {
+		svcCopy.Labels = map[string]string{}
+	}
+	for key, value := range desired.GetLabels() {
+		svcCopy.Labels[key] = value
+	}
+	if svc, err = o.kubeClientSet.CoreV1().Services(minio.GetNamespace()).Update(context.TODO(), svcCopy, metav1.UpdateOptions{}); err != nil {
+		o.recorder.Eventf(minio, apicorev1.EventTypeWarning, EventReasonServiceSyncFailed, "Repair service %s failed: %v", desired.GetName(), err)
+		return nil, err
+	}
+	o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonServiceRepaired, "Repaired ports, selector and labels of service %s", desired.GetName())
+	return svc, nil
 }
 
 // wait for all pod ready
@@ -235,13 +373,8 @@ func (o *operator) waitForPodReady(pod *apicorev1.Pod, timeout time.Duration) er
 			continue
 		}
 
-		for _, condition := range latestPod.Status.Conditions {
-			switch condition.Type {
-			case apicorev1.PodReady:
-				if condition.Status == apicorev1.ConditionTrue {
-					return nil
-				}
-			}
+		if IsPodReady(latestPod) {
+			return nil
 		}
 	}
 
@@ -289,18 +422,22 @@ func updateNodeAllocatedInfo(nodes map[int][]string, node string) {
 }
 
 // operator represent operator
-func (o *operator) syncMinioApplication(minioobject *crapiv1alpha1.Minio, timeout time.Duration) error {
+// syncMinioApplication wait until minio is online and create buckets, every candidate credential is tried in order,
+// the credential accepted by minio is returned
+func (o *operator) syncMinioApplication(minioobject *crapiv1alpha1.Minio, candidates []crapiv1alpha1.Credential, timeout time.Duration) (crapiv1alpha1.Credential, error) {
 	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
 	defer cancel()
 
 	var (
 		minioClient *minio.Client
+		accepted    crapiv1alpha1.Credential
 		err         error
 	)
 
 	var (
-		endpoint  = fmt.Sprintf("%s.%s:9000", getExternalServiceName(minioobject), minioobject.GetNamespace())
-		createOpt = minio.MakeBucketOptions{Region: "cn-north-1", ObjectLocking: true}
+		endpoint = getMinioEndpoint(minioobject)
+		// minio refuses to create buckets in a location other than its region, empty is the default region
+		createOpt = minio.MakeBucketOptions{Region: settingsEnvValue(minioobject, "MINIO_REGION"), ObjectLocking: true}
 	)
 
 	// for not in erasure codeed mode, ObjectLocking feature is not supported
@@ -311,38 +448,75 @@ func (o *operator) syncMinioApplication(minioobject *crapiv1alpha1.Minio, timeou
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return accepted, ctx.Err()
 		default:
 			time.Sleep(10 * time.Second)
 		}
 
-		if minioClient, err = minio.New(
-			endpoint,
-			&minio.Options{
-				Creds:  credentials.NewStaticV4(minioobject.Spec.Credential.AccessKey, minioobject.Spec.Credential.SecretKey, ""),
-				Secure: false},
-		); err != nil {
-			continue
-		}
-
-		if !minioClient.IsOnline() {
+		if minioClient, accepted, err = connectMinio(ctx, endpoint, candidates, createOpt); err != nil {
+			klog.Errorf("get || create testbucket failed: %v", err)
 			continue
 		}
-		// 由于minio没有提供检测server是不是已经初始化完的api的, 因此这里通过创建一个testbucket来确认minio是不是已经初始化完成了
-		_, err = minioClient.GetBucketLocation(context.Background(), "testbucket")
-		if err != nil {
-			if err := minioClient.MakeBucket(context.TODO(), "testbucket", createOpt); err != nil {
-				klog.Errorf("get || create testbucket failed: %v", err)
-				continue
-			}
+		if accepted != minioobject.Spec.Credential {
+			klog.Infof("%s/%s still accepts the previous credential %s", minioobject.GetNamespace(), minioobject.GetName(), credentialString(accepted))
 		}
 		// if detacted minio is online , we should this is ok, event if create bucket failed
 		if strings.Compare(minioobject.Status.Inited, "Ok") != 0 {
 			for _, bucketName := range minioobject.Spec.Buckets {
-				if err := minioClient.MakeBucket(context.TODO(), bucketName, createOpt); err != nil {
+				if err := minioClient.MakeBucket(ctx, bucketName, createOpt); err != nil {
 					klog.Errorf("create minio bucket failed %v", err)
+					o.recorder.Eventf(minioobject, apicorev1.EventTypeWarning, EventReasonBucketCreateFailed, "Create bucket %s failed: %v", bucketName, err)
 				}
 			}
 		}
+		o.collectApplicationMetrics(ctx, minioobject, minioClient, endpoint)
+		return accepted, nil
+	}
+}
+
+// connectMinio return a client of the first credential which is accepted by minio
+func connectMinio(ctx context.Context, endpoint string, candidates []crapiv1alpha1.Credential, createOpt minio.MakeBucketOptions) (*minio.Client, crapiv1alpha1.Credential, error) {
+	var lastErr error
+	for _, credential := range candidates {
+		minioClient, err := newMinioClient(endpoint, credential)
+		if err != nil {
+			return nil, credential, err
+		}
+		if !minioClient.IsOnline() {
+			return nil, credential, fmt.Errorf("minio %s is offline", endpoint)
+		}
+		// 由于minio没有提供检测server是不是已经初始化完的api的, 因此这里通过创建一个testbucket来确认minio是不是已经初始化完成了
+		if _, err = minioClient.GetBucketLocation(ctx, "testbucket"); err != nil {
+			if isCredentialRejected(err) {
+				lastErr = err
+				continue
+			}
+			if err = minioClient.MakeBucket(ctx, "testbucket", createOpt); err != nil {
+				return nil, credential, err
+			}
+		}
+		return minioClient, credential, nil
+	}
+	return nil, crapiv1alpha1.Credential{}, fmt.Errorf("all credentials are rejected: %v", lastErr)
+}
+
+// newMinioClient create minio-go client for the given endpoint and credential
+func newMinioClient(endpoint string, credential crapiv1alpha1.Credential) (*minio.Client, error) {
+	return minio.New(
+		endpoint,
+		&minio.Options{
+			Creds:  credentials.NewStaticV4(credential.AccessKey, credential.SecretKey, ""),
+			Secure: false},
+	)
+}
+
+// collectApplicationMetrics probe the health of minio cluster and count its buckets
+func (o *operator) collectApplicationMetrics(ctx context.Context, minioobject *crapiv1alpha1.Minio, minioClient *minio.Client, endpoint string) {
+	o.metrics.clusterHealth.WithLabelValues(minioobject.GetNamespace(), minioobject.GetName()).Set(boolToFloat64(probeClusterHealth(ctx, "http://"+endpoint)))
+	buckets, err := minioClient.ListBuckets(ctx)
+	if err != nil {
+		klog.Errorf("list buckets of %s/%s failed: %v", minioobject.GetNamespace(), minioobject.GetName(), err)
+		return
 	}
+	o.metrics.buckets.WithLabelValues(minioobject.GetNamespace(), minioobject.GetName()).Set(float64(len(buckets)))
 }
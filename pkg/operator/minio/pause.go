@@ -0,0 +1,45 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+// syncPaused only refresh the status of a paused minio, nothing owned by it is created, updated or deleted
+func (o *operator) syncPaused(minio *crapiv1alpha1.Minio) error {
+	// defaulter only fills the replicas which the ready members are counted against, spec is not persisted
+	crapiv1alpha1.MinioDefaulter(minio)
+	o.setCondition(minio, crapiv1alpha1.MinioConditionPaused, metav1.ConditionTrue, PausedReasonAnnotated,
+		fmt.Sprintf("Reconciliation is paused by annotation %s", crapiv1alpha1.PausedAnnotation), true)
+	if err := o.syncReplicaStatus(minio); err != nil {
+		return fmt.Errorf("%s/%s count ready members failed %v", minio.GetNamespace(), minio.GetName(), err)
+	}
+	if _, err := o.minioClient.MiniooperatorV1alpha1().Minios(minio.GetNamespace()).UpdateStatus(context.TODO(), minio, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("Update minio apps status failed %v", err)
+	}
+	return nil
+}
+
+// markResumed flip the Paused condition of a minio which was paused before, the status is updated with the other conditions
+func (o *operator) markResumed(minio *crapiv1alpha1.Minio) {
+	if meta.IsStatusConditionTrue(minio.Status.Conditions, crapiv1alpha1.MinioConditionPaused) {
+		o.setCondition(minio, crapiv1alpha1.MinioConditionPaused, metav1.ConditionFalse, PausedReasonResumed, "Reconciliation is resumed", true)
+	}
+}
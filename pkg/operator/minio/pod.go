@@ -5,29 +5,38 @@ import (
 	"path"
 
 	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
 	apicorev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func newPod(podName string, minio *crapiv1alpha1.Minio, nodeName string) *apicorev1.Pod {
 	// use fqdn to commuite each other
+	ports := getMinioPorts(minio)
 	var serverEndPoint string
 	if minio.Spec.Replicas == 1 {
 		serverEndPoint = "/data"
 	} else {
-		// pod-name-{0..N}.service-name.namespace.svc.cluster.local
-		serverEndPoint = fmt.Sprintf("http://%s-{0...%d}.%s.%s.svc.cluster.local/data", getPodNamePrefix(minio), minio.Spec.Replicas-1, getInternalServiceName(minio), minio.GetNamespace())
+		// pod-name-{0..N}.service-name.namespace.svc.cluster.local:port
+		serverEndPoint = fmt.Sprintf("http://%s-{0...%d}.%s.%s.svc.cluster.local:%d/data", getPodNamePrefix(minio), minio.Spec.Replicas-1, getInternalServiceName(minio), minio.GetNamespace(), ports.s3)
 	}
+	annotations := getResourceAnnotations(minio, nodeName)
+	annotations[crconfig.MinioCredentialHash] = credentialHash(minio.Spec.Credential)
+	annotations[crconfig.MinioPorts] = ports.String()
+	if hash := settingsHash(minio); hash != "" {
+		annotations[crconfig.MinioSettingsHash] = hash
+	}
+	kesVolumes, kesMounts := encryptionVolumes(minio)
 	var pod = &apicorev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            podName,
 			Namespace:       minio.GetNamespace(),
 			Labels:          getResourceLabels(minio),
-			Annotations:     getResourceAnnotations(minio, nodeName),
+			Annotations:     annotations,
 			OwnerReferences: getResourceOwnerReference(minio),
 		},
 		Spec: apicorev1.PodSpec{
-			Volumes: []apicorev1.Volume{
+			Volumes: append([]apicorev1.Volume{
 				{
 					Name: podName,
 					VolumeSource: apicorev1.VolumeSource{
@@ -36,40 +45,32 @@ func newPod(podName string, minio *crapiv1alpha1.Minio, nodeName string) *apicor
 						},
 					},
 				},
-			},
+			}, kesVolumes...),
 			Containers: []apicorev1.Container{
 				{
 					Name:       minio.GetName(),
 					Image:      minio.Spec.Image,
 					Command:    []string{},
-					Args:       []string{"server", "--console-address=0.0.0.0:9001", serverEndPoint},
+					Args:       []string{"server", fmt.Sprintf("--address=:%d", ports.s3), fmt.Sprintf("--console-address=:%d", ports.console), serverEndPoint},
 					WorkingDir: "",
-					Ports:      []apicorev1.ContainerPort{},
-					Env: []apicorev1.EnvVar{
-						{
-							Name:  "MINIO_ACCESS_KEY",
-							Value: minio.Spec.Credential.AccessKey,
-						},
-						{
-							Name:  "MINIO_SECRET_KEY",
-							Value: minio.Spec.Credential.SecretKey,
-						},
-						{
-							Name:  "MINIO_ROOT_USER",
-							Value: minio.Spec.Credential.AccessKey,
-						},
-						{
-							Name:  "MINIO_ROOT_PASSWORD",
-							Value: minio.Spec.Credential.SecretKey,
-						},
+					Ports: []apicorev1.ContainerPort{
+						{Name: "http", ContainerPort: ports.s3, Protocol: apicorev1.ProtocolTCP},
+						{Name: "api", ContainerPort: ports.console, Protocol: apicorev1.ProtocolTCP},
 					},
+					Env: append([]apicorev1.EnvVar{
+						credentialEnvVar("MINIO_ACCESS_KEY", minio, credentialAccessKey),
+						credentialEnvVar("MINIO_SECRET_KEY", minio, credentialSecretKey),
+						credentialEnvVar("MINIO_ROOT_USER", minio, credentialAccessKey),
+						credentialEnvVar("MINIO_ROOT_PASSWORD", minio, credentialSecretKey),
+					}, settingsEnv(minio)...),
+					EnvFrom:   minio.Spec.EnvFrom,
 					Resources: apicorev1.ResourceRequirements{},
-					VolumeMounts: []apicorev1.VolumeMount{
+					VolumeMounts: append([]apicorev1.VolumeMount{
 						{
 							Name:      podName,
 							MountPath: "/data",
 						},
-					},
+					}, kesMounts...),
 					SecurityContext: &apicorev1.SecurityContext{},
 					Stdin:           false,
 					StdinOnce:       false,
@@ -0,0 +1,99 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+// Render return the objects the operator creates for minio on a cluster whose ready nodes are nodes, nothing is read
+// from or written to the cluster. objects which depend on the state of the cluster are not rendered: the console
+// secret, the prometheus secret, the site replication job and the credential secret if the credential is referenced
+// by v1beta1. the KES identity is a placeholder
+func Render(minioobject *crapiv1alpha1.Minio, nodes []string) ([]runtime.Object, error) {
+	minio := minioobject.DeepCopy()
+	crapiv1alpha1.MinioDefaulter(minio)
+	limitReplicasToNodes(minio, nodes)
+
+	objects := []runtime.Object{newInternalService(minio), newExternalService(minio)}
+	if _, ok := minio.GetAnnotations()[crapiv1alpha1.CredentialSecretAnnotation]; !ok {
+		objects = append(objects, newCredentialSecret(minio))
+	}
+	if minio.Spec.Replicas > 1 {
+		objects = append(objects, newPodDisruptionBudget(minio))
+	}
+	policies := newNetworkPolicies(minio)
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		objects = append(objects, policies[name])
+	}
+	if console := minio.Spec.Console; console != nil {
+		objects = append(objects, newConsoleDeployment(minio), newConsoleService(minio))
+		if console.Ingress != nil {
+			objects = append(objects, newConsoleIngress(minio))
+		}
+	}
+	if encryption := minio.Spec.Encryption; encryption != nil && encryption.KES != nil {
+		identity := fmt.Sprintf("<identity of the certificate in secret %s>", encryption.ClientCertSecret)
+		objects = append(objects, newKESDeployment(minio, identity), newKESService(minio))
+	}
+	if logging := minio.Spec.Logging; logging != nil && logging.Collector != nil {
+		claim, err := newLogCollectorClaim(minio)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, claim, newLogCollectorDeployment(minio), newLogCollectorService(minio))
+	}
+	if monitoring := minio.Spec.Monitoring; monitoring != nil {
+		public := isPrometheusPublic(minio)
+		if monitoring.ServiceMonitor {
+			objects = append(objects, newMonitor(minio, "ServiceMonitor", public))
+		}
+		if monitoring.PodMonitor {
+			objects = append(objects, newMonitor(minio, "PodMonitor", public))
+		}
+	}
+
+	// members are placed the same way as syncPods places them on an empty cluster
+	nodeResPoll := map[int][]string{0: append([]string(nil), nodes...)}
+	for index := 0; index < int(minio.Spec.Replicas); index++ {
+		podName := getPodName(index, minio)
+		pickedNode := nodeNameForSchedulePod(podName, minio, nodeResPoll)
+		if pickedNode == "" {
+			return nil, fmt.Errorf("no ready node is available for pod %s", podName)
+		}
+		objects = append(objects, newPod(podName, minio, pickedNode))
+	}
+
+	for _, object := range objects {
+		if !object.GetObjectKind().GroupVersionKind().Empty() {
+			continue
+		}
+		kinds, _, err := kubescheme.Scheme.ObjectKinds(object)
+		if err != nil {
+			return nil, err
+		}
+		object.GetObjectKind().SetGroupVersionKind(kinds[0])
+	}
+	return objects, nil
+}
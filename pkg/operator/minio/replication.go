@@ -0,0 +1,415 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/signer"
+	batchv1 "k8s.io/api/batch/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// site replication is read through the admin api, but sites are added by a job running `mc admin replicate add`,
+// because the admin api encrypts the credentials of sites in the request body
+
+const (
+	siteReplicationInfoPath = "/minio/admin/v3/site-replication/info"
+	// emptyPayloadHash is the sha256 of an empty body, admin api requires signed payload
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	// siteReplicationJobBackoffLimit is the retries of the job before the condition turns to ConfigureFailed
+	siteReplicationJobBackoffLimit = 3
+)
+
+// site is a member of site replication
+type site struct {
+	name string
+	// endpoint is the url of minio with scheme
+	endpoint   string
+	credential crapiv1alpha1.Credential
+}
+
+// siteReplicationInfo is the response of the site replication info api
+type siteReplicationInfo struct {
+	Enabled bool   `json:"enabled"`
+	Name    string `json:"name,omitempty"`
+	Sites   []struct {
+		Name     string `json:"name"`
+		Endpoint string `json:"endpoints"`
+	} `json:"sites,omitempty"`
+}
+
+// syncSiteReplication add the sites of spec.siteReplication if they are not replicated yet and record the health of
+// replication in the SiteReplicationHealthy condition. problems of sites are reported by the condition, only errors
+// of kubernetes api are returned
+func (o *operator) syncSiteReplication(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential) error {
+	if minio.Spec.SiteReplication == nil {
+		meta.RemoveStatusCondition(&minio.Status.Conditions, crapiv1alpha1.MinioConditionSiteReplicationHealthy)
+		return nil
+	}
+	sites, problem, err := o.resolveSites(minio, accepted)
+	if err != nil {
+		return err
+	}
+	if problem != "" {
+		o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonInvalidPeer, problem)
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 30*time.Second)
+	defer cancel()
+	info, err := getSiteReplicationInfo(ctx, sites[0])
+	if err != nil {
+		o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonAdminAPIFailed, fmt.Sprintf("Get site replication info failed: %v", err))
+		return nil
+	}
+	if !isReplicated(info, sites) {
+		return o.configureSiteReplication(minio, sites)
+	}
+	if err = o.removeSiteReplicationJob(minio); err != nil {
+		return err
+	}
+
+	var offline []string
+	for _, s := range sites {
+		if !probeClusterHealth(ctx, s.endpoint) {
+			offline = append(offline, s.name)
+		}
+	}
+	if len(offline) > 0 {
+		o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonSiteOffline, fmt.Sprintf("Sites %s are offline", strings.Join(offline, ", ")))
+		return nil
+	}
+	o.setSiteReplicationCondition(minio, metav1.ConditionTrue, SiteReplicationReasonReplicated, fmt.Sprintf("%d sites are replicated", len(sites)))
+	return nil
+}
+
+// resolveSites return this minio and its peers as sites, this minio always comes first.
+// problem describes the peer which can not be resolved
+func (o *operator) resolveSites(minio *crapiv1alpha1.Minio, accepted crapiv1alpha1.Credential) ([]site, string, error) {
+	replication := minio.Spec.SiteReplication
+	if replication.Endpoint != "" {
+		if u, err := url.Parse(replication.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Sprintf("Endpoint %q is not a http(s) url", replication.Endpoint), nil
+		}
+	}
+	sites := []site{{name: replication.Name, endpoint: getSiteEndpoint(minio), credential: accepted}}
+	if len(replication.Peers) == 0 {
+		return nil, "No peer is set", nil
+	}
+	names := map[string]bool{replication.Name: true}
+	for index, peer := range replication.Peers {
+		var s site
+		switch {
+		case peer.Minio != nil && peer.Endpoint == "":
+			namespace := peer.Minio.Namespace
+			if namespace == "" {
+				namespace = minio.GetNamespace()
+			}
+			peerMinio, err := o.minioLister.Minios(namespace).Get(peer.Minio.Name)
+			if err != nil {
+				if k8serror.IsNotFound(err) {
+					return nil, fmt.Sprintf("Minio %s/%s of peer %d is not found", namespace, peer.Minio.Name, index), nil
+				}
+				return nil, "", err
+			}
+			credential, err := o.readCredential(namespace, getCredentialSecretName(peerMinio))
+			if err != nil {
+				if k8serror.IsNotFound(err) {
+					return nil, fmt.Sprintf("Minio %s/%s of peer %d is not initialized", namespace, peer.Minio.Name, index), nil
+				}
+				return nil, "", err
+			}
+			s = site{name: peer.Name, endpoint: getSiteEndpoint(peerMinio), credential: credential}
+			if s.name == "" {
+				s.name = peerMinio.GetName()
+			}
+		case peer.Minio == nil && peer.Endpoint != "":
+			if peer.Name == "" || peer.CredentialSecret == "" {
+				return nil, fmt.Sprintf("Name and credentialSecret are required by external peer %d", index), nil
+			}
+			if replication.Endpoint == "" {
+				return nil, fmt.Sprintf("Endpoint is required by external peer %s, it can not reach the address inside the kubernetes cluster", peer.Name), nil
+			}
+			if _, err := url.Parse(peer.Endpoint); err != nil {
+				return nil, fmt.Sprintf("Endpoint of peer %s is invalid: %v", peer.Name, err), nil
+			}
+			credential, err := o.readCredential(minio.GetNamespace(), peer.CredentialSecret)
+			if err != nil {
+				if k8serror.IsNotFound(err) {
+					return nil, fmt.Sprintf("Credential secret %s of peer %s is not found", peer.CredentialSecret, peer.Name), nil
+				}
+				return nil, "", err
+			}
+			s = site{name: peer.Name, endpoint: peer.Endpoint, credential: credential}
+		default:
+			return nil, fmt.Sprintf("Exactly one of minio and endpoint must be set in peer %d", index), nil
+		}
+		if names[s.name] {
+			return nil, fmt.Sprintf("Site name %s is used by more than one site", s.name), nil
+		}
+		names[s.name] = true
+		sites = append(sites, s)
+	}
+	return sites, "", nil
+}
+
+// readCredential read accessKey/secretKey from the secret
+func (o *operator) readCredential(namespace, name string) (crapiv1alpha1.Credential, error) {
+	secret, err := o.kubeClientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return crapiv1alpha1.Credential{}, err
+	}
+	return crapiv1alpha1.Credential{AccessKey: string(secret.Data[credentialAccessKey]), SecretKey: string(secret.Data[credentialSecretKey])}, nil
+}
+
+// configureSiteReplication run the job which adds all sites, the job is recreated when sites are changed
+func (o *operator) configureSiteReplication(minio *crapiv1alpha1.Minio, sites []site) error {
+	name := getSiteReplicationJobName(minio)
+	hash := sitesHash(sites)
+	job, err := o.kubeClientSet.BatchV1().Jobs(minio.GetNamespace()).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	if err == nil && job.GetAnnotations()[crconfig.MinioSiteReplicationHash] != hash {
+		// sites are changed, the next reconcile creates a new job
+		o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonConfiguring, "Sites are changed, waiting for the previous job to be deleted")
+		return o.removeSiteReplicationJob(minio)
+	}
+	if err == nil {
+		for _, condition := range job.Status.Conditions {
+			if condition.Type == batchv1.JobFailed && condition.Status == apicorev1.ConditionTrue {
+				o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonConfigureFailed,
+					fmt.Sprintf("Job %s failed, check its logs and delete it to retry: %s", name, condition.Message))
+				return nil
+			}
+		}
+		o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonConfiguring, fmt.Sprintf("Job %s is adding sites", name))
+		return nil
+	}
+
+	if err = o.syncSiteReplicationSecret(minio, sites); err != nil {
+		return err
+	}
+	if _, err = o.kubeClientSet.BatchV1().Jobs(minio.GetNamespace()).Create(context.TODO(), newSiteReplicationJob(minio, sites, hash), metav1.CreateOptions{}); err != nil && !k8serror.IsAlreadyExists(err) {
+		return err
+	}
+	o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonSiteReplicationJob, "Created job %s to add %d sites", name, len(sites))
+	o.setSiteReplicationCondition(minio, metav1.ConditionFalse, SiteReplicationReasonConfiguring, fmt.Sprintf("Job %s is adding sites", name))
+	return nil
+}
+
+// syncSiteReplicationSecret write the aliases of sites into the secret used by the job, mc reads alias from
+// MC_HOST_<alias> environment variables
+func (o *operator) syncSiteReplicationSecret(minio *crapiv1alpha1.Minio, sites []site) error {
+	data := map[string][]byte{}
+	for _, s := range sites {
+		alias, err := url.Parse(s.endpoint)
+		if err != nil {
+			return err
+		}
+		alias.User = url.UserPassword(s.credential.AccessKey, s.credential.SecretKey)
+		data["MC_HOST_"+s.name] = []byte(alias.String())
+	}
+	secrets := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace())
+	secret, err := secrets.Get(context.TODO(), getSiteReplicationJobName(minio), metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return err
+		}
+		_, err = secrets.Create(context.TODO(), &apicorev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            getSiteReplicationJobName(minio),
+				Namespace:       minio.GetNamespace(),
+				Labels:          getResourceLabels(minio),
+				Annotations:     getResourceAnnotations(minio, ""),
+				OwnerReferences: getResourceOwnerReference(minio),
+			},
+			Type: apicorev1.SecretTypeOpaque,
+			Data: data,
+		}, metav1.CreateOptions{})
+		return err
+	}
+	secretCopy := secret.DeepCopy()
+	secretCopy.Data = data
+	_, err = secrets.Update(context.TODO(), secretCopy, metav1.UpdateOptions{})
+	return err
+}
+
+// removeSiteReplicationJob delete the job and its secret, they are not needed once sites are replicated
+func (o *operator) removeSiteReplicationJob(minio *crapiv1alpha1.Minio) error {
+	propagation := metav1.DeletePropagationBackground
+	name := getSiteReplicationJobName(minio)
+	if err := o.kubeClientSet.BatchV1().Jobs(minio.GetNamespace()).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation}); err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	if err := o.kubeClientSet.CoreV1().Secrets(minio.GetNamespace()).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serror.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// setSiteReplicationCondition update the SiteReplicationHealthy condition
+func (o *operator) setSiteReplicationCondition(minio *crapiv1alpha1.Minio, status metav1.ConditionStatus, reason, message string) {
+	o.setCondition(minio, crapiv1alpha1.MinioConditionSiteReplicationHealthy, status, reason, message, reason == SiteReplicationReasonConfiguring)
+}
+
+// setCondition update the condition of minio, an event is recorded when its status or reason is changed, the event
+// is a warning if status is not true and the condition is not progressing
+func (o *operator) setCondition(minio *crapiv1alpha1.Minio, conditionType string, status metav1.ConditionStatus, reason, message string, progressing bool) {
+	previous := meta.FindStatusCondition(minio.Status.Conditions, conditionType)
+	if previous == nil || previous.Status != status || previous.Reason != reason {
+		eventType := apicorev1.EventTypeNormal
+		if status != metav1.ConditionTrue && !progressing {
+			eventType = apicorev1.EventTypeWarning
+		}
+		o.recorder.Event(minio, eventType, reason, message)
+	}
+	meta.SetStatusCondition(&minio.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: minio.GetGeneration(),
+	})
+}
+
+// getSiteReplicationInfo call the site replication info api with the root credential of s
+func getSiteReplicationInfo(ctx context.Context, s site) (*siteReplicationInfo, error) {
+	info := &siteReplicationInfo{}
+	if err := callAdminAPI(ctx, strings.TrimSuffix(s.endpoint, "/")+siteReplicationInfoPath, s.credential, info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// callAdminAPI send a signed GET request to the admin api of minio and decode the json response into out
+func callAdminAPI(ctx context.Context, apiURL string, credential crapiv1alpha1.Credential, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	req = signer.SignV4(*req, credential.AccessKey, credential.SecretKey, "", "us-east-1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// isReplicated return true if every site is in the site replication, sites are matched by name or endpoint
+// because sites may have been added by hand
+func isReplicated(info *siteReplicationInfo, sites []site) bool {
+	if !info.Enabled {
+		return false
+	}
+	for _, s := range sites {
+		found := false
+		for _, peer := range info.Sites {
+			if peer.Name == s.name || strings.EqualFold(strings.TrimSuffix(peer.Endpoint, "/"), strings.TrimSuffix(s.endpoint, "/")) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func newSiteReplicationJob(minio *crapiv1alpha1.Minio, sites []site, hash string) *batchv1.Job {
+	backoffLimit := int32(siteReplicationJobBackoffLimit)
+	args := []string{"admin", "replicate", "add"}
+	for _, s := range sites {
+		args = append(args, s.name)
+	}
+	annotations := getResourceAnnotations(minio, "")
+	annotations[crconfig.MinioSiteReplicationHash] = hash
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            getSiteReplicationJobName(minio),
+			Namespace:       minio.GetNamespace(),
+			Labels:          getResourceLabels(minio),
+			Annotations:     annotations,
+			OwnerReferences: getResourceOwnerReference(minio),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: apicorev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: getSiteReplicationSelector(minio)},
+				Spec: apicorev1.PodSpec{
+					RestartPolicy: apicorev1.RestartPolicyNever,
+					Containers: []apicorev1.Container{
+						{
+							Name:  "mc",
+							Image: minio.Spec.SiteReplication.Image,
+							Args:  args,
+							EnvFrom: []apicorev1.EnvFromSource{
+								{SecretRef: &apicorev1.SecretEnvSource{LocalObjectReference: apicorev1.LocalObjectReference{Name: getSiteReplicationJobName(minio)}}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// sitesHash return a short hash of names and endpoints of sites, credentials are excluded so that credential
+// rotation does not re-add sites
+func sitesHash(sites []site) string {
+	h := sha256.New()
+	for _, s := range sites {
+		h.Write([]byte(s.name + "\x00" + s.endpoint + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// getSiteEndpoint return the url which other sites reach minio through
+func getSiteEndpoint(minio *crapiv1alpha1.Minio) string {
+	if replication := minio.Spec.SiteReplication; replication != nil && replication.Endpoint != "" {
+		return replication.Endpoint
+	}
+	return "http://" + getMinioEndpoint(minio)
+}
+
+// getSiteReplicationSelector return the labels of site replication job pods, they must not be selected as members
+func getSiteReplicationSelector(minio *crapiv1alpha1.Minio) map[string]string {
+	return map[string]string{crconfig.MinioSiteReplicationLabel: minio.GetName()}
+}
+
+func getSiteReplicationJobName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-site-replication"
+}
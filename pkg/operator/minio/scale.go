@@ -0,0 +1,115 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apicorev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+)
+
+// memberIndex return the index of the member named name, pods of members and their nodes in annotations of minio are
+// both keyed by the name
+func memberIndex(minio *crapiv1alpha1.Minio, name string) (int, bool) {
+	prefix := getPodNamePrefix(minio) + "-"
+	if !strings.HasPrefix(name, prefix) {
+		return 0, false
+	}
+	index, err := strconv.Atoi(strings.TrimPrefix(name, prefix))
+	if err != nil || getPodName(index, minio) != name {
+		return 0, false
+	}
+	return index, true
+}
+
+// validateReplicas refuse to shrink the pool, the endpoints of a pool are fixed when it is formed and minio can only
+// decommission a pool into other pools, so dropping members of the only pool loses the data they hold. the pool is
+// sized by the pods of members, terminating ones included
+func (o *operator) validateReplicas(minio *crapiv1alpha1.Minio) error {
+	pods, err := o.listMemberPods(minio)
+	if err != nil {
+		return err
+	}
+	var poolSize int
+	for _, pod := range pods {
+		if index, ok := memberIndex(minio, pod.GetName()); ok && index+1 > poolSize {
+			poolSize = index + 1
+		}
+	}
+	if poolSize <= int(minio.Spec.Replicas) {
+		return nil
+	}
+	return fmt.Errorf("the pool has %d members and can not be shrunk to %d replicas", poolSize, minio.Spec.Replicas)
+}
+
+// cleanupMembers remove what is left by members which no longer exist: nodes recorded for members beyond replicas
+// which have no pod, and pods of minio which are not members. it runs after validateReplicas, so no member beyond
+// replicas is running. data under spec.hostPath on those nodes is kept
+func (o *operator) cleanupMembers(minio *crapiv1alpha1.Minio) error {
+	pods, err := o.listMemberPods(minio)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(pods))
+	var removed []string
+	for _, pod := range pods {
+		exists[pod.GetName()] = true
+		if _, ok := memberIndex(minio, pod.GetName()); ok || pod.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if err = o.kubeClientSet.CoreV1().Pods(pod.GetNamespace()).Delete(context.TODO(), pod.GetName(), metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+			return err
+		}
+		removed = append(removed, "pod "+pod.GetName())
+	}
+	annotations := map[string]interface{}{}
+	for key := range minio.GetAnnotations() {
+		if index, ok := memberIndex(minio, key); ok && index >= int(minio.Spec.Replicas) && !exists[key] {
+			annotations[key] = nil
+			removed = append(removed, "node of "+key)
+		}
+	}
+	if len(annotations) != 0 {
+		if err = o.patchAnnotations(minio, annotations); err != nil {
+			return err
+		}
+	}
+	if len(removed) != 0 {
+		sort.Strings(removed)
+		o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonStaleMembersRemoved, "Removed stale %s", strings.Join(removed, ", "))
+	}
+	return nil
+}
+
+// listMemberPods return the pods controlled by minio, all of them have the labels of members
+func (o *operator) listMemberPods(minio *crapiv1alpha1.Minio) ([]*apicorev1.Pod, error) {
+	pods, err := o.podLister.Pods(minio.GetNamespace()).List(labels.SelectorFromSet(getResourceLabels(minio)))
+	if err != nil {
+		return nil, err
+	}
+	var controlled []*apicorev1.Pod
+	for _, pod := range pods {
+		if metav1.IsControlledBy(pod, minio) {
+			controlled = append(controlled, pod)
+		}
+	}
+	return controlled, nil
+}
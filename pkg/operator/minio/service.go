@@ -2,6 +2,7 @@ package minio
 
 import (
 	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
 	apicorev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -17,18 +18,7 @@ func newExternalService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
 			OwnerReferences: getResourceOwnerReference(minio),
 		},
 		Spec: apicorev1.ServiceSpec{
-			Ports: []apicorev1.ServicePort{
-				{
-					Name:       "api",
-					Port:       minio.Spec.Port.ApiPort,
-					TargetPort: intstr.IntOrString{IntVal: minio.Spec.Port.ApiPort},
-				},
-				{
-					Name:       "http",
-					Port:       minio.Spec.Port.HttpPort,
-					TargetPort: intstr.IntOrString{IntVal: minio.Spec.Port.HttpPort},
-				},
-			},
+			Ports:    newServicePorts(minio),
 			Selector: getResourceLabels(minio),
 			Type:     apicorev1.ServiceTypeNodePort,
 		},
@@ -38,27 +28,19 @@ func newExternalService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
 }
 
 func newInternalService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
+	labels := getResourceLabels(minio)
+	// the internal service is the only one selected by monitors, the others share the labels of minio
+	labels[crconfig.MinioInternalServiceLabel] = minio.GetName()
 	svc := &apicorev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            getInternalServiceName(minio),
 			Namespace:       minio.GetNamespace(),
-			Labels:          getResourceLabels(minio),
+			Labels:          labels,
 			Annotations:     getResourceAnnotations(minio, ""),
 			OwnerReferences: getResourceOwnerReference(minio),
 		},
 		Spec: apicorev1.ServiceSpec{
-			Ports: []apicorev1.ServicePort{
-				{
-					Name:       "api",
-					Port:       minio.Spec.Port.ApiPort,
-					TargetPort: intstr.IntOrString{IntVal: minio.Spec.Port.ApiPort},
-				},
-				{
-					Name:       "http",
-					Port:       minio.Spec.Port.HttpPort,
-					TargetPort: intstr.IntOrString{IntVal: minio.Spec.Port.HttpPort},
-				},
-			},
+			Ports:      newServicePorts(minio),
 			Selector:   getResourceLabels(minio),
 			ClusterIPs: []string{},
 			ClusterIP:  "None",
@@ -68,3 +50,59 @@ func newInternalService(minio *crapiv1alpha1.Minio) *apicorev1.Service {
 	}
 	return svc
 }
+
+// newServicePorts return the ports of both services, target ports are the ports minio listens on
+func newServicePorts(minio *crapiv1alpha1.Minio) []apicorev1.ServicePort {
+	ports := getMinioPorts(minio)
+	return []apicorev1.ServicePort{
+		{
+			Name:       "api",
+			Port:       ports.console,
+			TargetPort: intstr.FromInt(int(ports.console)),
+		},
+		{
+			Name:       "http",
+			Port:       ports.s3,
+			TargetPort: intstr.FromInt(int(ports.s3)),
+		},
+	}
+}
+
+// servicePortsEqual compare the ports managed by operator, fields allocated by kubernetes(nodePort) are ignored
+func servicePortsEqual(actual, desired []apicorev1.ServicePort) bool {
+	if len(actual) != len(desired) {
+		return false
+	}
+	for i := range desired {
+		if actual[i].Name != desired[i].Name || actual[i].Port != desired[i].Port || actual[i].TargetPort != desired[i].TargetPort {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeServicePorts return desired ports, the nodePort allocated for a port with the same name is kept
+func mergeServicePorts(actual, desired []apicorev1.ServicePort) []apicorev1.ServicePort {
+	nodePorts := map[string]int32{}
+	for _, port := range actual {
+		nodePorts[port.Name] = port.NodePort
+	}
+	merged := make([]apicorev1.ServicePort, 0, len(desired))
+	for _, port := range desired {
+		if port.NodePort == 0 {
+			port.NodePort = nodePorts[port.Name]
+		}
+		merged = append(merged, port)
+	}
+	return merged
+}
+
+// labelsContain return true if all of expected labels are in labels, labels added by others are ignored
+func labelsContain(labels, expected map[string]string) bool {
+	for key, value := range expected {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
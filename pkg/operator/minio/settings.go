@@ -0,0 +1,134 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package minio
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+)
+
+// settingsEnv return the environment variables of spec.settings, spec.identity, spec.encryption and spec.logging followed by spec.env, the latter wins
+// because kubernetes keeps the last one of duplicated names
+func settingsEnv(minio *crapiv1alpha1.Minio) []apicorev1.EnvVar {
+	var env []apicorev1.EnvVar
+	add := func(name, value string) {
+		if value != "" {
+			env = append(env, apicorev1.EnvVar{Name: name, Value: value})
+		}
+	}
+	if settings := minio.Spec.Settings; settings != nil {
+		if settings.Browser != nil {
+			add("MINIO_BROWSER", onOff(*settings.Browser))
+		}
+		add("MINIO_REGION", settings.Region)
+		add("MINIO_STORAGE_CLASS_STANDARD", settings.StorageClassStandard)
+		add("MINIO_STORAGE_CLASS_RRS", settings.StorageClassRRS)
+		add("MINIO_PROMETHEUS_AUTH_TYPE", settings.PrometheusAuthType)
+		add("MINIO_SCANNER_SPEED", settings.ScannerSpeed)
+		if compression := settings.Compression; compression != nil {
+			add("MINIO_COMPRESSION_ENABLE", onOff(compression.Enabled))
+			add("MINIO_COMPRESSION_ALLOW_ENCRYPTION", onOff(compression.AllowEncryption))
+			add("MINIO_COMPRESSION_EXTENSIONS", strings.Join(compression.Extensions, ","))
+			add("MINIO_COMPRESSION_MIME_TYPES", strings.Join(compression.MimeTypes, ","))
+		}
+	}
+	if monitoring := minio.Spec.Monitoring; monitoring != nil && monitoring.Public && (minio.Spec.Settings == nil || minio.Spec.Settings.PrometheusAuthType == "") {
+		add("MINIO_PROMETHEUS_AUTH_TYPE", prometheusAuthPublic)
+	}
+	env = append(env, identityEnv(minio)...)
+	env = append(env, encryptionEnv(minio)...)
+	env = append(env, loggingEnv(minio)...)
+	return append(env, minio.Spec.Env...)
+}
+
+// settingsEnvValue return the value minio reads for the environment variable name, it is the last one in settingsEnv
+func settingsEnvValue(minio *crapiv1alpha1.Minio, name string) string {
+	var value string
+	for _, env := range settingsEnv(minio) {
+		if env.Name == name {
+			value = env.Value
+		}
+	}
+	return value
+}
+
+// settingsHash return a short hash of the settings which minio reads at startup, it is empty if nothing is set
+// so that pods created before settings are introduced are not restarted
+func settingsHash(minio *crapiv1alpha1.Minio) string {
+	env := settingsEnv(minio)
+	if len(env) == 0 && len(minio.Spec.EnvFrom) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(struct {
+		Env     []apicorev1.EnvVar
+		EnvFrom []apicorev1.EnvFromSource
+	}{env, minio.Spec.EnvFrom})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// restartOutdatedMember delete one member which is running with outdated settings once all members are ready,
+// it is recreated by syncPods. it returns true while some members are still outdated
+func (o *operator) restartOutdatedMember(minio *crapiv1alpha1.Minio) (bool, error) {
+	desiredHash := settingsHash(minio)
+	var (
+		outdated []*apicorev1.Pod
+		allReady = true
+	)
+	for index := 0; index < int(minio.Spec.Replicas); index++ {
+		pod, err := o.podLister.Pods(minio.GetNamespace()).Get(getPodName(index, minio))
+		if err != nil {
+			if k8serror.IsNotFound(err) {
+				allReady = false
+				continue
+			}
+			return false, err
+		}
+		if pod.GetDeletionTimestamp() != nil || !IsPodReady(pod) {
+			allReady = false
+		}
+		if pod.GetDeletionTimestamp() == nil && pod.GetAnnotations()[crconfig.MinioSettingsHash] != desiredHash {
+			outdated = append(outdated, pod)
+		}
+	}
+	if len(outdated) == 0 {
+		return false, nil
+	}
+	if !allReady {
+		// wait for the member restarted last time
+		return true, nil
+	}
+	pod := outdated[0]
+	o.recorder.Eventf(minio, apicorev1.EventTypeNormal, EventReasonSettingsChanged, "Restarting pod %s to apply the new settings, %d members are outdated", pod.GetName(), len(outdated))
+	if err := o.kubeClientSet.CoreV1().Pods(pod.GetNamespace()).Delete(context.TODO(), pod.GetName(), metav1.DeleteOptions{}); err != nil && !k8serror.IsNotFound(err) {
+		return true, err
+	}
+	return true, nil
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -13,11 +13,21 @@
 package minio
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
+	"strings"
 
 	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
 	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+	appsv1 "k8s.io/api/apps/v1"
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func getMinioAppName(obj *crapiv1alpha1.Minio, target string) string {
@@ -67,3 +77,163 @@ func getInternalServiceName(minio *crapiv1alpha1.Minio) string {
 func getExternalServiceName(minio *crapiv1alpha1.Minio) string {
 	return minio.GetName() + "-service"
 }
+
+func getCredentialSecretName(minio *crapiv1alpha1.Minio) string {
+	return minio.GetName() + "-credential"
+}
+
+// minioPorts is the resolved port configuration of a minio, listen addresses, container ports, service ports
+// and endpoints are all derived from it
+type minioPorts struct {
+	// s3 is the port of the S3 api, spec.port.http_port
+	s3 int32
+	// console is the port of the embedded console, spec.port.apiport
+	console int32
+}
+
+// getMinioPorts resolve the ports of minio, objects read from listers are not defaulted so defaults are applied here
+func getMinioPorts(minio *crapiv1alpha1.Minio) minioPorts {
+	ports := minioPorts{s3: minio.Spec.Port.HttpPort, console: minio.Spec.Port.ApiPort}
+	if ports.s3 == 0 {
+		ports.s3 = 9000
+	}
+	if ports.console == 0 {
+		ports.console = 9001
+	}
+	return ports
+}
+
+// String is recorded in the annotation of pods, members listening on other ports must be restarted
+func (p minioPorts) String() string {
+	return fmt.Sprintf("%d,%d", p.s3, p.console)
+}
+
+// getMinioEndpoint return the endpoint used by operator to talk with minio
+func getMinioEndpoint(minio *crapiv1alpha1.Minio) string {
+	return fmt.Sprintf("%s.%s:%d", getExternalServiceName(minio), minio.GetNamespace(), getMinioPorts(minio).s3)
+}
+
+// IsPodReady return true if the PodReady condition of pod is true
+func IsPodReady(pod *apicorev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == apicorev1.PodReady {
+			return condition.Status == apicorev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// probeClusterHealth call the cluster health check api of minio at baseURL, it returns true only if cluster has write quorum
+func probeClusterHealth(ctx context.Context, baseURL string) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(baseURL, "/")+"/minio/health/cluster", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// syncReplicaStatus count the ready members and record them with the selector of members, they are used by
+// kubectl and the scale subresource
+func (o *operator) syncReplicaStatus(minio *crapiv1alpha1.Minio) error {
+	var ready int32
+	for index := 0; index < int(minio.Spec.Replicas); index++ {
+		pod, err := o.podLister.Pods(minio.GetNamespace()).Get(getPodName(index, minio))
+		if err != nil {
+			if k8serror.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if pod.GetDeletionTimestamp() == nil && IsPodReady(pod) {
+			ready++
+		}
+	}
+	minio.Status.ReadyReplicas = ready
+	minio.Status.Selector = labels.SelectorFromSet(getResourceLabels(minio)).String()
+	minio.Status.Phase = crapiv1alpha1.MinioPhaseRunning
+	if ready < minio.Spec.Replicas {
+		minio.Status.Phase = crapiv1alpha1.MinioPhaseDegraded
+	}
+	return nil
+}
+
+// patchAnnotations merge annotations into minio, a nil value removes the annotation. minio is never written back by
+// Update because its spec is defaulted and holds the credential resolved from the referenced secret in memory
+func (o *operator) patchAnnotations(minio *crapiv1alpha1.Minio, annotations map[string]interface{}) error {
+	patch, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": annotations}})
+	if err != nil {
+		return err
+	}
+	updated, err := o.minioClient.MiniooperatorV1alpha1().Minios(minio.GetNamespace()).Patch(context.TODO(), minio.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return err
+	}
+	if minio.Annotations == nil {
+		minio.Annotations = map[string]string{}
+	}
+	for key, value := range annotations {
+		if value == nil {
+			delete(minio.Annotations, key)
+			continue
+		}
+		minio.Annotations[key] = fmt.Sprint(value)
+	}
+	minio.SetResourceVersion(updated.GetResourceVersion())
+	return nil
+}
+
+// getComponentSelector return the labels of the pods of a component running beside minio(console, KES, log
+// collector), they must not match the selector of minio services
+func getComponentSelector(label string, minio *crapiv1alpha1.Minio) map[string]string {
+	return map[string]string{label: minio.GetName()}
+}
+
+// syncDeployment create or update the deployment of a component, it is only updated when the spec hash changes.
+// it returns true if the deployment is changed
+func (o *operator) syncDeployment(desired *appsv1.Deployment) (bool, error) {
+	deployments := o.kubeClientSet.AppsV1().Deployments(desired.GetNamespace())
+	deployment, err := deployments.Get(context.TODO(), desired.GetName(), metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return false, err
+		}
+		_, err = deployments.Create(context.TODO(), desired, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if deployment.GetAnnotations()[crconfig.MinioSpecHash] == desired.GetAnnotations()[crconfig.MinioSpecHash] {
+		return false, nil
+	}
+	deploymentCopy := deployment.DeepCopy()
+	deploymentCopy.Labels = desired.Labels
+	deploymentCopy.Annotations = desired.Annotations
+	deploymentCopy.Spec = desired.Spec
+	_, err = deployments.Update(context.TODO(), deploymentCopy, metav1.UpdateOptions{})
+	return err == nil, err
+}
+
+// removeComponent delete the deployment and service of a component named name, deleteOthers removes the objects
+// only some components have. the service is deleted last because its existence in the lister tells whether there
+// is anything to remove, a removal failed halfway is retried. it returns true if the component is removed
+func (o *operator) removeComponent(namespace, name string, deleteOthers func() error) (bool, error) {
+	if _, err := o.serviceLister.Services(namespace).Get(name); err != nil {
+		return false, ignoreNotFound(err)
+	}
+	propagation := metav1.DeletePropagationBackground
+	if err := o.kubeClientSet.AppsV1().Deployments(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{PropagationPolicy: &propagation}); ignoreNotFound(err) != nil {
+		return false, err
+	}
+	if deleteOthers != nil {
+		if err := deleteOthers(); err != nil {
+			return false, err
+		}
+	}
+	if err := o.kubeClientSet.CoreV1().Services(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); ignoreNotFound(err) != nil {
+		return false, err
+	}
+	return true, nil
+}
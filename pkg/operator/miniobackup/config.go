@@ -0,0 +1,26 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import "time"
+
+// reasons of events recorded on MinioBackup objects
+const (
+	EventReasonBackupStarted   = "BackupStarted"
+	EventReasonBackupCompleted = "BackupCompleted"
+	EventReasonBackupFailed    = "BackupFailed"
+	EventReasonCleanupFailed   = "CleanupFailed"
+)
+
+// progressInterval is how often the status of a running backup is updated
+const progressInterval = 30 * time.Second
@@ -0,0 +1,234 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"k8s.io/klog/v2"
+)
+
+// progress is the statistics of a running backup, it is updated by the copier and read by the status reporter
+type progress struct {
+	objectsCopied  int64
+	objectsSkipped int64
+	bytesCopied    int64
+}
+
+func (p *progress) load() (objectsCopied, objectsSkipped, bytesCopied int64) {
+	return atomic.LoadInt64(&p.objectsCopied), atomic.LoadInt64(&p.objectsSkipped), atomic.LoadInt64(&p.bytesCopied)
+}
+
+// copier copy buckets of the source minio into the target. objects which are not changed since the base backup
+// are copied inside the target, so every backup is complete on its own and can be deleted independently
+type copier struct {
+	source *minio.Client
+	target *Target
+	// base is the layout of the base backup in the target bucket, nil means a full backup
+	base     *Layout
+	progress progress
+}
+
+// run back up the given buckets, all buckets of source are backed up if buckets is empty.
+// it returns the buckets which are backed up
+func (c *copier) run(ctx context.Context, buckets []string) ([]string, error) {
+	if len(buckets) == 0 {
+		bucketInfos, err := c.source.ListBuckets(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list buckets of source failed: %v", err)
+		}
+		for _, info := range bucketInfos {
+			buckets = append(buckets, info.Name)
+		}
+	}
+	exists, err := c.target.Client.BucketExists(ctx, c.target.Layout.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("check target bucket failed: %v", err)
+	}
+	if !exists {
+		if err = c.target.Client.MakeBucket(ctx, c.target.Layout.Bucket, minio.MakeBucketOptions{Region: c.target.Region}); err != nil {
+			return nil, fmt.Errorf("create target bucket failed: %v", err)
+		}
+	}
+	for _, bucket := range buckets {
+		settings, err := readBucketSettings(ctx, c.source, bucket)
+		if err != nil {
+			return nil, fmt.Errorf("read settings of bucket %s failed: %v", bucket, err)
+		}
+		if err = putJSON(ctx, c.target.Client, c.target.Layout.Bucket, c.target.Layout.BucketSettingsKey(bucket), settings); err != nil {
+			return nil, fmt.Errorf("write settings of bucket %s failed: %v", bucket, err)
+		}
+		if err = c.copyBucket(ctx, bucket); err != nil {
+			return nil, fmt.Errorf("back up bucket %s failed: %v", bucket, err)
+		}
+	}
+	return buckets, nil
+}
+
+func (c *copier) copyBucket(ctx context.Context, bucket string) error {
+	for info := range c.source.ListObjects(ctx, bucket, minio.ListObjectsOptions{Recursive: true}) {
+		if info.Err != nil {
+			return info.Err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if c.copyFromBase(ctx, bucket, info) {
+			atomic.AddInt64(&c.progress.objectsSkipped, 1)
+			continue
+		}
+		if err := c.copyFromSource(ctx, bucket, info); err != nil {
+			return fmt.Errorf("copy object %s failed: %v", info.Key, err)
+		}
+		atomic.AddInt64(&c.progress.objectsCopied, 1)
+		atomic.AddInt64(&c.progress.bytesCopied, info.Size)
+	}
+	return nil
+}
+
+// copyFromBase copy the object inside the target if it is not changed since the base backup, it returns false
+// if the object must be read from source
+func (c *copier) copyFromBase(ctx context.Context, bucket string, info minio.ObjectInfo) bool {
+	if c.base == nil {
+		return false
+	}
+	baseKey := c.base.ObjectKey(bucket, info.Key)
+	baseInfo, err := c.target.Client.StatObject(ctx, c.base.Bucket, baseKey, minio.StatObjectOptions{})
+	if err != nil {
+		return false
+	}
+	if userMetadata(baseInfo, metaSourceETag) != info.ETag || userMetadata(baseInfo, metaSourceModTime) != formatModTime(info.LastModified) {
+		return false
+	}
+	// ComposeObject copies metadata of a single source, and falls back to multipart copy for large objects
+	_, err = c.target.Client.ComposeObject(ctx,
+		minio.CopyDestOptions{Bucket: c.target.Layout.Bucket, Object: c.target.Layout.ObjectKey(bucket, info.Key)},
+		minio.CopySrcOptions{Bucket: c.base.Bucket, Object: baseKey})
+	if err != nil {
+		klog.Warningf("copy %s from base backup failed, read it from source: %v", baseKey, err)
+		return false
+	}
+	return true
+}
+
+func (c *copier) copyFromSource(ctx context.Context, bucket string, info minio.ObjectInfo) error {
+	object, err := c.source.GetObject(ctx, bucket, info.Key, minio.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	stat, err := object.Stat()
+	if err != nil {
+		return err
+	}
+	metadata := map[string]string{}
+	for key, value := range stat.UserMetadata {
+		metadata[key] = value
+	}
+	metadata[metaSourceETag] = stat.ETag
+	metadata[metaSourceModTime] = formatModTime(stat.LastModified)
+	_, err = c.target.Client.PutObject(ctx, c.target.Layout.Bucket, c.target.Layout.ObjectKey(bucket, info.Key), object, stat.Size, minio.PutObjectOptions{
+		ContentType:  stat.ContentType,
+		UserMetadata: metadata,
+	})
+	return err
+}
+
+// readBucketSettings read the configuration of bucket, configuration which is not set is left empty
+func readBucketSettings(ctx context.Context, client *minio.Client, bucket string) (*BucketSettings, error) {
+	settings := &BucketSettings{Name: bucket}
+	policy, err := client.GetBucketPolicy(ctx, bucket)
+	if err = ignoreErrorCode(err, "NoSuchBucketPolicy"); err != nil {
+		return nil, err
+	}
+	settings.Policy = policy
+	versioning, err := client.GetBucketVersioning(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	settings.Versioning = versioning.Status
+	if objectLock, _, _, _, err := client.GetObjectLockConfig(ctx, bucket); err == nil {
+		settings.ObjectLocking = objectLock == "Enabled"
+	}
+	tags, err := client.GetBucketTagging(ctx, bucket)
+	if err = ignoreErrorCode(err, "NoSuchTagSet"); err != nil {
+		return nil, err
+	}
+	if tags != nil {
+		settings.Tags = tags.ToMap()
+	}
+	lifecycle, err := client.GetBucketLifecycle(ctx, bucket)
+	if err = ignoreErrorCode(err, "NoSuchLifecycleConfiguration"); err != nil {
+		return nil, err
+	}
+	settings.Lifecycle = lifecycle
+	return settings, nil
+}
+
+// putJSON write v as a json object
+func putJSON(ctx context.Context, client *minio.Client, bucket, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutObject(ctx, bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{ContentType: "application/json"})
+	return err
+}
+
+// removePrefix delete all objects under prefix of bucket
+func removePrefix(ctx context.Context, client *minio.Client, bucket, prefix string) error {
+	objects := client.ListObjects(ctx, bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true})
+	var firstErr error
+	// drain all errors, RemoveObjects blocks until its error channel is consumed
+	for removeErr := range client.RemoveObjects(ctx, bucket, objects, minio.RemoveObjectsOptions{}) {
+		if firstErr == nil {
+			firstErr = fmt.Errorf("remove %s failed: %v", removeErr.ObjectName, removeErr.Err)
+		}
+	}
+	return firstErr
+}
+
+// ignoreErrorCode return nil if err is a minio error response with one of codes
+func ignoreErrorCode(err error, codes ...string) error {
+	if err == nil {
+		return nil
+	}
+	code := minio.ToErrorResponse(err).Code
+	for _, c := range codes {
+		if code == c {
+			return nil
+		}
+	}
+	return err
+}
+
+// userMetadata return the user metadata of key, the case of keys depends on the server
+func userMetadata(info minio.ObjectInfo, key string) string {
+	for k, v := range info.UserMetadata {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func formatModTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
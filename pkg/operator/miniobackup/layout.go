@@ -0,0 +1,90 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import (
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+// a backup is stored under <prefix>/<backup name>/ of the target bucket:
+//   <bucket>/<object key>                      objects of every backed up bucket
+//   .minio-backup/buckets/<bucket>.json        settings of every backed up bucket
+//   .minio-backup/manifest.json                written after all buckets are backed up
+// bucket names never start with a dot, so the metadata directory does not collide with buckets.
+
+const (
+	metadataDir = ".minio-backup"
+
+	// user metadata recorded on every object of a backup, it is used to find unchanged objects
+	metaSourceETag    = "Source-Etag"
+	metaSourceModTime = "Source-Mtime"
+)
+
+// Manifest describes a completed backup
+type Manifest struct {
+	Minio          string    `json:"minio"`
+	Namespace      string    `json:"namespace"`
+	Buckets        []string  `json:"buckets"`
+	Base           string    `json:"base,omitempty"`
+	StartTime      time.Time `json:"startTime"`
+	CompletionTime time.Time `json:"completionTime"`
+}
+
+// BucketSettings is the configuration of a bucket which is restored together with its objects
+type BucketSettings struct {
+	Name          string                   `json:"name"`
+	Policy        string                   `json:"policy,omitempty"`
+	Versioning    string                   `json:"versioning,omitempty"`
+	ObjectLocking bool                     `json:"objectLocking,omitempty"`
+	Tags          map[string]string        `json:"tags,omitempty"`
+	Lifecycle     *lifecycle.Configuration `json:"lifecycle,omitempty"`
+}
+
+// Layout locate the objects of one backup inside the target bucket
+type Layout struct {
+	Bucket string
+	// Root is the key prefix of the backup, it always ends with a slash
+	Root string
+}
+
+// NewLayout return the layout of backup name under prefix of bucket
+func NewLayout(bucket, prefix, name string) Layout {
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return Layout{Bucket: bucket, Root: prefix + name + "/"}
+}
+
+// BucketPrefix is the key prefix of all objects of the given source bucket
+func (l Layout) BucketPrefix(bucket string) string {
+	return l.Root + bucket + "/"
+}
+
+// ObjectKey is the key of the given source object
+func (l Layout) ObjectKey(bucket, key string) string {
+	return l.BucketPrefix(bucket) + key
+}
+
+// BucketSettingsKey is the key of the settings of the given source bucket
+func (l Layout) BucketSettingsKey(bucket string) string {
+	return l.Root + metadataDir + "/buckets/" + bucket + ".json"
+}
+
+// ManifestKey is the key of the manifest
+func (l Layout) ManifestKey() string {
+	return l.Root + metadataDir + "/manifest.json"
+}
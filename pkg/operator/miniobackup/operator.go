@@ -0,0 +1,387 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crlisterv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/listers/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+	croperator "github.com/3Xpl0it3r/minio-operator/pkg/operator"
+	miniooperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/minio"
+)
+
+type operator struct {
+	kubeClientSet kubernetes.Interface
+	crClientSet   crclientset.Interface
+	backupLister  crlisterv1alpha1.MinioBackupLister
+	minioLister   crlisterv1alpha1.MinioLister
+	recorder      record.EventRecorder
+
+	// running keeps the backups being copied by this process, keyed by namespace/name
+	lock    sync.Mutex
+	running map[string]*runningBackup
+}
+
+// runningBackup is a backup being copied in background, reconcile only starts and cancels it
+type runningBackup struct {
+	cancel context.CancelFunc
+}
+
+func NewOperator(kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, backupLister crlisterv1alpha1.MinioBackupLister, minioLister crlisterv1alpha1.MinioLister, recorder record.EventRecorder) croperator.Operator {
+	return &operator{
+		kubeClientSet: kubeClientSet,
+		crClientSet:   crClientSet,
+		backupLister:  backupLister,
+		minioLister:   minioLister,
+		recorder:      recorder,
+		running:       map[string]*runningBackup{},
+	}
+}
+
+func (o *operator) Reconcile(object interface{}) error {
+	key := object.(string)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get the namespace and name from key: %v : %v", object, err))
+		return nil
+	}
+	backup, err := o.backupLister.MinioBackups(namespace).Get(name)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			o.cancel(key)
+			return nil
+		}
+		return fmt.Errorf("%s/%s get backup failed %v", namespace, name, err)
+	}
+	backupCopy := backup.DeepCopy()
+
+	if backupCopy.GetDeletionTimestamp() != nil {
+		o.cancel(key)
+		return o.finalize(backupCopy)
+	}
+	if !hasFinalizer(backupCopy) {
+		backupCopy.Finalizers = append(backupCopy.Finalizers, crconfig.MinioBackupFinalizer)
+		if backupCopy, err = o.crClientSet.MiniooperatorV1alpha1().MinioBackups(namespace).Update(context.TODO(), backupCopy, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("%s/%s add finalizer failed %v", namespace, name, err)
+		}
+	}
+
+	switch backupCopy.Status.Phase {
+	case "", crapiv1alpha1.BackupPhasePending:
+		return o.start(key, backupCopy)
+	case crapiv1alpha1.BackupPhaseRunning:
+		// the backup is running in this process, or the process which ran it has exited
+		if o.isRunning(key) {
+			return nil
+		}
+		// the lister may not have seen the result of a backup which is just finished
+		latest, err := o.crClientSet.MiniooperatorV1alpha1().MinioBackups(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s/%s get backup failed %v", namespace, name, err)
+		}
+		if latest.Status.Phase != crapiv1alpha1.BackupPhaseRunning {
+			return nil
+		}
+		return o.launch(key, latest)
+	}
+	return nil
+}
+
+// start validate the backup, pick its base and mark it as running
+func (o *operator) start(key string, backup *crapiv1alpha1.MinioBackup) error {
+	if err := ValidateTarget(backup.Spec.Target); err != nil {
+		return o.fail(backup, err)
+	}
+	if _, err := o.minioLister.Minios(backup.GetNamespace()).Get(backup.Spec.Minio); err != nil {
+		if k8serror.IsNotFound(err) {
+			return o.fail(backup, fmt.Errorf("minio %s is not found", backup.Spec.Minio))
+		}
+		return err
+	}
+	base, err := o.findBase(backup)
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	backup.Status = crapiv1alpha1.MinioBackupStatus{Phase: crapiv1alpha1.BackupPhaseRunning, StartTime: &now, Base: base}
+	if backup, err = o.crClientSet.MiniooperatorV1alpha1().MinioBackups(backup.GetNamespace()).UpdateStatus(context.TODO(), backup, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("%s update status failed %v", key, err)
+	}
+	if base == "" {
+		o.recorder.Eventf(backup, apicorev1.EventTypeNormal, EventReasonBackupStarted, "Full backup of minio %s is started", backup.Spec.Minio)
+	} else {
+		o.recorder.Eventf(backup, apicorev1.EventTypeNormal, EventReasonBackupStarted, "Incremental backup of minio %s based on %s is started", backup.Spec.Minio, base)
+	}
+	return o.launch(key, backup)
+}
+
+// launch connect to the source minio and the target, and copy buckets in background
+func (o *operator) launch(key string, backup *crapiv1alpha1.MinioBackup) error {
+	minio, err := o.minioLister.Minios(backup.GetNamespace()).Get(backup.Spec.Minio)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return o.fail(backup, fmt.Errorf("minio %s is not found", backup.Spec.Minio))
+		}
+		return err
+	}
+	source, err := miniooperator.NewApplicationClient(context.TODO(), o.kubeClientSet, minio)
+	if err != nil {
+		return fmt.Errorf("%s connect to minio %s failed %v", key, minio.GetName(), err)
+	}
+	target, err := OpenTarget(context.TODO(), o.kubeClientSet, backup.GetNamespace(), backup.Spec.Target, backup.GetName(), o.volumeServer(backup))
+	if err != nil {
+		return fmt.Errorf("%s open target failed %v", key, err)
+	}
+	c := &copier{source: source, target: target}
+	if backup.Status.Base != "" {
+		base := targetLayout(backup.Spec.Target, backup.Status.Base)
+		c.base = &base
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningBackup{cancel: cancel}
+	o.lock.Lock()
+	o.running[key] = running
+	o.lock.Unlock()
+	go o.run(ctx, key, running, backup, c)
+	return nil
+}
+
+// run copy buckets and record the result into status, it returns without touching status if it is canceled
+func (o *operator) run(ctx context.Context, key string, running *runningBackup, backup *crapiv1alpha1.MinioBackup, c *copier) {
+	defer func() {
+		// a canceled backup is deleted, finalize still needs the volume server to delete its data
+		if server := o.volumeServer(backup); server != nil && ctx.Err() == nil {
+			if err := server.Remove(context.TODO(), o.kubeClientSet); err != nil {
+				klog.Errorf("%s remove volume server failed: %v", key, err)
+			}
+		}
+		running.cancel()
+		o.lock.Lock()
+		if o.running[key] == running {
+			delete(o.running, key)
+		}
+		o.lock.Unlock()
+	}()
+	klog.Infof("%s begin to back up minio %s into %s", key, backup.Spec.Minio, c.target.Location)
+
+	progressDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-progressDone:
+				return
+			case <-ticker.C:
+				if err := o.updateStatus(backup, func(status *crapiv1alpha1.MinioBackupStatus) {
+					status.Location = c.target.Location
+					status.ObjectsCopied, status.ObjectsSkipped, status.BytesCopied = c.progress.load()
+				}); err != nil {
+					klog.Errorf("%s update progress failed: %v", key, err)
+				}
+			}
+		}
+	}()
+	buckets, runErr := c.run(ctx, backup.Spec.Buckets)
+	if runErr == nil {
+		runErr = putJSON(ctx, c.target.Client, c.target.Layout.Bucket, c.target.Layout.ManifestKey(), &Manifest{
+			Minio:          backup.Spec.Minio,
+			Namespace:      backup.GetNamespace(),
+			Buckets:        buckets,
+			Base:           backup.Status.Base,
+			StartTime:      backup.Status.StartTime.Time,
+			CompletionTime: time.Now(),
+		})
+	}
+	close(progressDone)
+	if ctx.Err() != nil {
+		// the backup is deleted
+		return
+	}
+
+	if err := o.updateStatus(backup, func(status *crapiv1alpha1.MinioBackupStatus) {
+		now := metav1.Now()
+		status.CompletionTime = &now
+		status.Location = c.target.Location
+		status.Buckets = buckets
+		status.ObjectsCopied, status.ObjectsSkipped, status.BytesCopied = c.progress.load()
+		if runErr != nil {
+			status.Phase = crapiv1alpha1.BackupPhaseFailed
+			status.Message = runErr.Error()
+			return
+		}
+		status.Phase = crapiv1alpha1.BackupPhaseCompleted
+		status.Message = ""
+	}); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s update status failed %v", key, err))
+		return
+	}
+	objectsCopied, objectsSkipped, bytesCopied := c.progress.load()
+	if runErr != nil {
+		o.recorder.Eventf(backup, apicorev1.EventTypeWarning, EventReasonBackupFailed, "Backup failed: %v", runErr)
+	} else {
+		o.recorder.Eventf(backup, apicorev1.EventTypeNormal, EventReasonBackupCompleted, "Backup completed, %d objects(%d bytes) copied, %d unchanged objects skipped", objectsCopied, bytesCopied, objectsSkipped)
+	}
+}
+
+// finalize delete the backup data in target and remove the finalizer
+func (o *operator) finalize(backup *crapiv1alpha1.MinioBackup) error {
+	if !hasFinalizer(backup) {
+		return nil
+	}
+	key := backup.GetNamespace() + "/" + backup.GetName()
+	if backup.Status.StartTime != nil && ValidateTarget(backup.Spec.Target) == nil {
+		target, err := OpenTarget(context.TODO(), o.kubeClientSet, backup.GetNamespace(), backup.Spec.Target, backup.GetName(), o.volumeServer(backup))
+		if err == nil {
+			err = removePrefix(context.TODO(), target.Client, target.Layout.Bucket, target.Layout.Root)
+		}
+		if err != nil {
+			if err != ErrVolumeServerNotReady {
+				o.recorder.Eventf(backup, apicorev1.EventTypeWarning, EventReasonCleanupFailed, "Delete backup data failed, remove finalizer %s to skip it: %v", crconfig.MinioBackupFinalizer, err)
+			}
+			return fmt.Errorf("%s delete backup data failed %v", key, err)
+		}
+	}
+	if server := o.volumeServer(backup); server != nil {
+		if err := server.Remove(context.TODO(), o.kubeClientSet); err != nil {
+			return fmt.Errorf("%s remove volume server failed %v", key, err)
+		}
+	}
+	finalizers := make([]string, 0, len(backup.Finalizers))
+	for _, finalizer := range backup.Finalizers {
+		if finalizer != crconfig.MinioBackupFinalizer {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+	backup.Finalizers = finalizers
+	if _, err := o.crClientSet.MiniooperatorV1alpha1().MinioBackups(backup.GetNamespace()).Update(context.TODO(), backup, metav1.UpdateOptions{}); err != nil && !k8serror.IsNotFound(err) {
+		return fmt.Errorf("%s remove finalizer failed %v", key, err)
+	}
+	return nil
+}
+
+// fail mark the backup as failed without retrying
+func (o *operator) fail(backup *crapiv1alpha1.MinioBackup, reason error) error {
+	o.recorder.Eventf(backup, apicorev1.EventTypeWarning, EventReasonBackupFailed, "Backup failed: %v", reason)
+	// the volume server may be started by an earlier attempt
+	if server := o.volumeServer(backup); server != nil {
+		if err := server.Remove(context.TODO(), o.kubeClientSet); err != nil {
+			return fmt.Errorf("remove volume server failed %v", err)
+		}
+	}
+	return o.updateStatus(backup, func(status *crapiv1alpha1.MinioBackupStatus) {
+		now := metav1.Now()
+		status.Phase = crapiv1alpha1.BackupPhaseFailed
+		status.CompletionTime = &now
+		status.Message = reason.Error()
+	})
+}
+
+// updateStatus apply mutate on the latest status of backup
+func (o *operator) updateStatus(backup *crapiv1alpha1.MinioBackup, mutate func(status *crapiv1alpha1.MinioBackupStatus)) error {
+	client := o.crClientSet.MiniooperatorV1alpha1().MinioBackups(backup.GetNamespace())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := client.Get(context.TODO(), backup.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(&latest.Status)
+		_, err = client.UpdateStatus(context.TODO(), latest, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// findBase return the latest completed backup of the same minio in the same target
+func (o *operator) findBase(backup *crapiv1alpha1.MinioBackup) (string, error) {
+	backups, err := o.backupLister.MinioBackups(backup.GetNamespace()).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	var base *crapiv1alpha1.MinioBackup
+	for _, item := range backups {
+		if item.GetName() == backup.GetName() || item.GetDeletionTimestamp() != nil || item.Status.Phase != crapiv1alpha1.BackupPhaseCompleted {
+			continue
+		}
+		if item.Spec.Minio != backup.Spec.Minio || !sameTarget(item.Spec.Target, backup.Spec.Target) || item.Status.CompletionTime == nil {
+			continue
+		}
+		if base == nil || base.Status.CompletionTime.Before(item.Status.CompletionTime) {
+			base = item
+		}
+	}
+	if base == nil {
+		return "", nil
+	}
+	return base.GetName(), nil
+}
+
+// volumeServer return the temporary minio pod shared by users of the persistent volume claim, nil for other targets
+func (o *operator) volumeServer(backup *crapiv1alpha1.MinioBackup) *VolumeServer {
+	claim := backup.Spec.Target.PersistentVolumeClaim
+	if claim == nil {
+		return nil
+	}
+	image := claim.Image
+	if image == "" {
+		minio := &crapiv1alpha1.Minio{}
+		if source, err := o.minioLister.Minios(backup.GetNamespace()).Get(backup.Spec.Minio); err == nil {
+			minio = source.DeepCopy()
+		}
+		crapiv1alpha1.MinioDefaulter(minio)
+		image = minio.Spec.Image
+	}
+	return NewVolumeServer(backup.GetNamespace(), claim.ClaimName, image, *metav1.NewControllerRef(backup, crapiv1alpha1.SchemeGroupVersion.WithKind("MinioBackup")))
+}
+
+func (o *operator) isRunning(key string) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	_, ok := o.running[key]
+	return ok
+}
+
+func (o *operator) cancel(key string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if running, ok := o.running[key]; ok {
+		running.cancel()
+		delete(o.running, key)
+	}
+}
+
+func hasFinalizer(backup *crapiv1alpha1.MinioBackup) bool {
+	for _, finalizer := range backup.Finalizers {
+		if finalizer == crconfig.MinioBackupFinalizer {
+			return true
+		}
+	}
+	return false
+}
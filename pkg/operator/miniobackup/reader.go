@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// the functions below read a backup back, they are used by restores
+
+// ReadManifest read the manifest of the backup, it fails if the backup has not completed
+func (t *Target) ReadManifest(ctx context.Context) (*Manifest, error) {
+	manifest := &Manifest{}
+	if err := getJSON(ctx, t.Client, t.Layout.Bucket, t.Layout.ManifestKey(), manifest); err != nil {
+		return nil, fmt.Errorf("read manifest of %s failed: %v", t.Location, err)
+	}
+	return manifest, nil
+}
+
+// ReadBucketSettings read the settings of a backed up bucket
+func (t *Target) ReadBucketSettings(ctx context.Context, bucket string) (*BucketSettings, error) {
+	settings := &BucketSettings{}
+	if err := getJSON(ctx, t.Client, t.Layout.Bucket, t.Layout.BucketSettingsKey(bucket), settings); err != nil {
+		return nil, fmt.Errorf("read settings of bucket %s failed: %v", bucket, err)
+	}
+	return settings, nil
+}
+
+// CreateBucket create the bucket described by settings and apply its configuration
+func CreateBucket(ctx context.Context, client *minio.Client, settings *BucketSettings) error {
+	if err := client.MakeBucket(ctx, settings.Name, minio.MakeBucketOptions{ObjectLocking: settings.ObjectLocking}); err != nil {
+		return err
+	}
+	if settings.Policy != "" {
+		if err := client.SetBucketPolicy(ctx, settings.Name, settings.Policy); err != nil {
+			return fmt.Errorf("set policy failed: %v", err)
+		}
+	}
+	// buckets with object locking are always versioned
+	if settings.Versioning != "" && !settings.ObjectLocking {
+		if err := client.SetBucketVersioning(ctx, settings.Name, minio.BucketVersioningConfiguration{Status: settings.Versioning}); err != nil {
+			return fmt.Errorf("set versioning failed: %v", err)
+		}
+	}
+	if len(settings.Tags) > 0 {
+		bucketTags, err := tags.NewTags(settings.Tags, false)
+		if err != nil {
+			return fmt.Errorf("parse tags failed: %v", err)
+		}
+		if err = client.SetBucketTagging(ctx, settings.Name, bucketTags); err != nil {
+			return fmt.Errorf("set tags failed: %v", err)
+		}
+	}
+	if settings.Lifecycle != nil && len(settings.Lifecycle.Rules) > 0 {
+		if err := client.SetBucketLifecycle(ctx, settings.Name, settings.Lifecycle); err != nil {
+			return fmt.Errorf("set lifecycle failed: %v", err)
+		}
+	}
+	return nil
+}
+
+// SourceETag return the etag of the source object which a backed up object was copied from
+func SourceETag(info minio.ObjectInfo) string {
+	return userMetadata(info, metaSourceETag)
+}
+
+// SourceMetadata return the user metadata of the source object which a backed up object was copied from
+func SourceMetadata(info minio.ObjectInfo) map[string]string {
+	metadata := map[string]string{}
+	for key, value := range info.UserMetadata {
+		if strings.EqualFold(key, metaSourceETag) || strings.EqualFold(key, metaSourceModTime) {
+			continue
+		}
+		metadata[key] = value
+	}
+	return metadata
+}
+
+// IsNotFound return true if err is returned for a missing bucket or object
+func IsNotFound(err error) bool {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NoSuchBucket":
+		return true
+	}
+	return false
+}
+
+// getJSON read a json object into v
+func getJSON(ctx context.Context, client *minio.Client, bucket, key string, v interface{}) error {
+	object, err := client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return err
+	}
+	defer object.Close()
+	return json.NewDecoder(object).Decode(v)
+}
@@ -0,0 +1,388 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackup
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	miniooperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/minio"
+)
+
+const (
+	// keys of the secret holding the credential of a target or a volume server
+	targetAccessKey = "accessKey"
+	targetSecretKey = "secretKey"
+
+	// volumeBucket is the bucket which holds backups stored on a persistent volume claim
+	volumeBucket = "backups"
+	volumePort   = 9000
+)
+
+// ErrVolumeServerNotReady is returned while the temporary minio pod of a persistent volume claim is starting
+var ErrVolumeServerNotReady = errors.New("volume server is not ready")
+
+// Target is the opened storage of one backup
+type Target struct {
+	Client *minio.Client
+	Layout Layout
+	// Location is a human readable location of the backup data
+	Location string
+	// Region is used when the target bucket is created
+	Region string
+}
+
+// OpenTarget connect to the storage of the backup with the given name, server must be set if the target is a
+// persistent volume claim, ErrVolumeServerNotReady is returned until its pod is ready
+func OpenTarget(ctx context.Context, kubeClientSet kubernetes.Interface, namespace string, target crapiv1alpha1.BackupTarget, backupName string, server *VolumeServer) (*Target, error) {
+	switch {
+	case target.S3 != nil:
+		s3 := target.S3
+		secret, err := kubeClientSet.CoreV1().Secrets(namespace).Get(ctx, s3.CredentialSecret, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("get credential secret of target failed: %v", err)
+		}
+		client, err := minio.New(s3.Endpoint, &minio.Options{
+			Creds:  credentials.NewStaticV4(string(secret.Data[targetAccessKey]), string(secret.Data[targetSecretKey]), ""),
+			Secure: s3.Secure,
+			Region: s3.Region,
+		})
+		if err != nil {
+			return nil, err
+		}
+		layout := targetLayout(target, backupName)
+		return &Target{Client: client, Layout: layout, Location: fmt.Sprintf("s3://%s/%s/%s", s3.Endpoint, s3.Bucket, layout.Root), Region: s3.Region}, nil
+	case target.PersistentVolumeClaim != nil:
+		if server == nil {
+			return nil, fmt.Errorf("no volume server is given for claim %s", target.PersistentVolumeClaim.ClaimName)
+		}
+		endpoint, credential, err := server.Ensure(ctx, kubeClientSet)
+		if err != nil {
+			return nil, err
+		}
+		client, err := minio.New(endpoint, &minio.Options{Creds: credentials.NewStaticV4(credential.AccessKey, credential.SecretKey, "")})
+		if err != nil {
+			return nil, err
+		}
+		layout := targetLayout(target, backupName)
+		return &Target{Client: client, Layout: layout, Location: fmt.Sprintf("pvc://%s/%s/%s", target.PersistentVolumeClaim.ClaimName, volumeBucket, layout.Root)}, nil
+	}
+	return nil, errors.New("neither s3 nor persistentVolumeClaim is set in target")
+}
+
+// targetLayout return the layout of the backup with the given name in target
+func targetLayout(target crapiv1alpha1.BackupTarget, backupName string) Layout {
+	if target.S3 != nil {
+		return NewLayout(target.S3.Bucket, target.S3.Prefix, backupName)
+	}
+	return NewLayout(volumeBucket, "", backupName)
+}
+
+// ValidateTarget return error if the target is not exactly one of s3 and persistentVolumeClaim
+func ValidateTarget(target crapiv1alpha1.BackupTarget) error {
+	if (target.S3 == nil) == (target.PersistentVolumeClaim == nil) {
+		return errors.New("exactly one of s3 and persistentVolumeClaim must be set in target")
+	}
+	if target.S3 != nil && (target.S3.Endpoint == "" || target.S3.Bucket == "" || target.S3.CredentialSecret == "") {
+		return errors.New("endpoint, bucket and credentialSecret are required by s3 target")
+	}
+	if target.PersistentVolumeClaim != nil && target.PersistentVolumeClaim.ClaimName == "" {
+		return errors.New("claimName is required by persistentVolumeClaim target")
+	}
+	return nil
+}
+
+// sameTarget return true if both targets store backups in the same place
+func sameTarget(a, b crapiv1alpha1.BackupTarget) bool {
+	switch {
+	case a.S3 != nil && b.S3 != nil:
+		return a.S3.Endpoint == b.S3.Endpoint && a.S3.Bucket == b.S3.Bucket && NewLayout("", a.S3.Prefix, "").Root == NewLayout("", b.S3.Prefix, "").Root
+	case a.PersistentVolumeClaim != nil && b.PersistentVolumeClaim != nil:
+		return a.PersistentVolumeClaim.ClaimName == b.PersistentVolumeClaim.ClaimName
+	}
+	return false
+}
+
+// VolumeServer is a temporary minio pod serving a persistent volume claim. minio does not support several servers on
+// one backend, so backups, prunes and restores on the same claim share one server. each of them is a owner of the pod
+// and its credential secret, which are deleted together with the last owner
+type VolumeServer struct {
+	Namespace string
+	// Name is the name of the pod and the prefix of its credential secret
+	Name      string
+	ClaimName string
+	// Image is used if the pod is not existed
+	Image string
+	Owner metav1.OwnerReference
+}
+
+// NewVolumeServer return the server of the claim used by owner
+func NewVolumeServer(namespace, claimName, image string, owner metav1.OwnerReference) *VolumeServer {
+	// the pod is shared, so none of its owners is the controller
+	owner.Controller = nil
+	return &VolumeServer{
+		Namespace: namespace,
+		Name:      claimName + "-volume",
+		ClaimName: claimName,
+		Image:     image,
+		Owner:     owner,
+	}
+}
+
+func (v *VolumeServer) secretName() string {
+	return v.Name + "-credential"
+}
+
+// Ensure create the pod and its credential secret if they are not existed and add Owner to their owners, it returns
+// the endpoint and credential of the pod once it is ready
+func (v *VolumeServer) Ensure(ctx context.Context, kubeClientSet kubernetes.Interface) (string, crapiv1alpha1.Credential, error) {
+	credential, err := v.ensureSecret(ctx, kubeClientSet)
+	if err != nil {
+		return "", credential, err
+	}
+	pods := kubeClientSet.CoreV1().Pods(v.Namespace)
+	pod, err := pods.Get(ctx, v.Name, metav1.GetOptions{})
+	if err != nil {
+		if !k8serror.IsNotFound(err) {
+			return "", credential, err
+		}
+		if _, err = pods.Create(ctx, v.newPod(), metav1.CreateOptions{}); err != nil && !k8serror.IsAlreadyExists(err) {
+			return "", credential, err
+		}
+		return "", credential, ErrVolumeServerNotReady
+	}
+	if pod.GetDeletionTimestamp() != nil {
+		// the last owner has released it
+		return "", credential, ErrVolumeServerNotReady
+	}
+	if err = v.adopt(pod, func() (metav1.Object, error) {
+		return pods.Get(ctx, v.Name, metav1.GetOptions{})
+	}, func(obj metav1.Object) error {
+		_, err := pods.Update(ctx, obj.(*apicorev1.Pod), metav1.UpdateOptions{})
+		return err
+	}); err != nil {
+		return "", credential, err
+	}
+	if pod.Status.Phase == apicorev1.PodFailed || pod.Status.Phase == apicorev1.PodSucceeded {
+		// restartPolicy is Always, but the pod may still be evicted
+		if err = pods.Delete(ctx, v.Name, metav1.DeleteOptions{}); err != nil && !k8serror.IsNotFound(err) {
+			return "", credential, err
+		}
+		return "", credential, ErrVolumeServerNotReady
+	}
+	if pod.Status.PodIP == "" || !miniooperator.IsPodReady(pod) {
+		return "", credential, ErrVolumeServerNotReady
+	}
+	return net.JoinHostPort(pod.Status.PodIP, fmt.Sprint(volumePort)), credential, nil
+}
+
+// Remove release the pod and its credential secret from Owner, they are deleted if Owner is the last owner
+func (v *VolumeServer) Remove(ctx context.Context, kubeClientSet kubernetes.Interface) error {
+	pods := kubeClientSet.CoreV1().Pods(v.Namespace)
+	if err := v.release(func() (metav1.Object, error) {
+		return pods.Get(ctx, v.Name, metav1.GetOptions{})
+	}, func(obj metav1.Object) error {
+		_, err := pods.Update(ctx, obj.(*apicorev1.Pod), metav1.UpdateOptions{})
+		return err
+	}, func(obj metav1.Object) error {
+		return pods.Delete(ctx, v.Name, deleteUnchanged(obj))
+	}); err != nil {
+		return err
+	}
+	secrets := kubeClientSet.CoreV1().Secrets(v.Namespace)
+	return v.release(func() (metav1.Object, error) {
+		return secrets.Get(ctx, v.secretName(), metav1.GetOptions{})
+	}, func(obj metav1.Object) error {
+		_, err := secrets.Update(ctx, obj.(*apicorev1.Secret), metav1.UpdateOptions{})
+		return err
+	}, func(obj metav1.Object) error {
+		return secrets.Delete(ctx, v.secretName(), deleteUnchanged(obj))
+	})
+}
+
+// adopt add Owner to the owners of obj, get return the latest obj when update conflicts
+func (v *VolumeServer) adopt(obj metav1.Object, get func() (metav1.Object, error), update func(metav1.Object) error) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if obj == nil {
+			latest, err := get()
+			if err != nil {
+				return err
+			}
+			obj = latest
+		}
+		owners := obj.GetOwnerReferences()
+		if indexOwner(owners, v.Owner) >= 0 {
+			return nil
+		}
+		obj.SetOwnerReferences(append(owners, v.Owner))
+		err := update(obj)
+		obj = nil
+		return err
+	})
+}
+
+// release remove Owner from the owners of the object returned by get, the object is deleted if no owner is left.
+// remove must fail with conflict if the object is changed, so that a owner added meanwhile is not lost
+func (v *VolumeServer) release(get func() (metav1.Object, error), update func(metav1.Object) error, remove func(metav1.Object) error) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := get()
+		if err != nil {
+			return err
+		}
+		owners := obj.GetOwnerReferences()
+		index := indexOwner(owners, v.Owner)
+		if index < 0 {
+			return nil
+		}
+		if len(owners) == 1 {
+			return remove(obj)
+		}
+		obj.SetOwnerReferences(append(owners[:index:index], owners[index+1:]...))
+		return update(obj)
+	})
+	if k8serror.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// deleteUnchanged return the options which delete obj only if it is not changed since it was read
+func deleteUnchanged(obj metav1.Object) metav1.DeleteOptions {
+	resourceVersion := obj.GetResourceVersion()
+	return metav1.DeleteOptions{Preconditions: &metav1.Preconditions{ResourceVersion: &resourceVersion}}
+}
+
+func indexOwner(owners []metav1.OwnerReference, owner metav1.OwnerReference) int {
+	for index := range owners {
+		if owners[index].UID == owner.UID {
+			return index
+		}
+	}
+	return -1
+}
+
+// ensureSecret return the credential of the pod, a random one is generated for a new pod
+func (v *VolumeServer) ensureSecret(ctx context.Context, kubeClientSet kubernetes.Interface) (crapiv1alpha1.Credential, error) {
+	secrets := kubeClientSet.CoreV1().Secrets(v.Namespace)
+	secret, err := secrets.Get(ctx, v.secretName(), metav1.GetOptions{})
+	if err == nil {
+		credential := crapiv1alpha1.Credential{AccessKey: string(secret.Data[targetAccessKey]), SecretKey: string(secret.Data[targetSecretKey])}
+		if secret.GetDeletionTimestamp() != nil {
+			return credential, ErrVolumeServerNotReady
+		}
+		return credential, v.adopt(secret, func() (metav1.Object, error) {
+			return secrets.Get(ctx, v.secretName(), metav1.GetOptions{})
+		}, func(obj metav1.Object) error {
+			_, err := secrets.Update(ctx, obj.(*apicorev1.Secret), metav1.UpdateOptions{})
+			return err
+		})
+	}
+	if !k8serror.IsNotFound(err) {
+		return crapiv1alpha1.Credential{}, err
+	}
+	credential := crapiv1alpha1.Credential{AccessKey: randomString(10), SecretKey: randomString(20)}
+	secret = &apicorev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            v.secretName(),
+			Namespace:       v.Namespace,
+			OwnerReferences: []metav1.OwnerReference{v.Owner},
+		},
+		Type: apicorev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			targetAccessKey: []byte(credential.AccessKey),
+			targetSecretKey: []byte(credential.SecretKey),
+		},
+	}
+	if _, err = secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+		if k8serror.IsAlreadyExists(err) {
+			// another owner created it meanwhile
+			return credential, ErrVolumeServerNotReady
+		}
+		return credential, err
+	}
+	return credential, nil
+}
+
+func (v *VolumeServer) newPod() *apicorev1.Pod {
+	secretEnv := func(name, key string) apicorev1.EnvVar {
+		return apicorev1.EnvVar{
+			Name: name,
+			ValueFrom: &apicorev1.EnvVarSource{
+				SecretKeyRef: &apicorev1.SecretKeySelector{
+					LocalObjectReference: apicorev1.LocalObjectReference{Name: v.secretName()},
+					Key:                  key,
+				},
+			},
+		}
+	}
+	return &apicorev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            v.Name,
+			Namespace:       v.Namespace,
+			OwnerReferences: []metav1.OwnerReference{v.Owner},
+		},
+		Spec: apicorev1.PodSpec{
+			Volumes: []apicorev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: apicorev1.VolumeSource{
+						PersistentVolumeClaim: &apicorev1.PersistentVolumeClaimVolumeSource{ClaimName: v.ClaimName},
+					},
+				},
+			},
+			Containers: []apicorev1.Container{
+				{
+					Name:  "minio",
+					Image: v.Image,
+					Args:  []string{"server", "--address=:" + fmt.Sprint(volumePort), "/data"},
+					Env: []apicorev1.EnvVar{
+						secretEnv("MINIO_ROOT_USER", targetAccessKey),
+						secretEnv("MINIO_ROOT_PASSWORD", targetSecretKey),
+					},
+					Ports: []apicorev1.ContainerPort{{Name: "api", ContainerPort: volumePort}},
+					ReadinessProbe: &apicorev1.Probe{
+						ProbeHandler: apicorev1.ProbeHandler{
+							HTTPGet: &apicorev1.HTTPGetAction{Path: "/minio/health/ready", Port: intstr.FromInt(volumePort)},
+						},
+						PeriodSeconds: 2,
+					},
+					VolumeMounts: []apicorev1.VolumeMount{{Name: "data", MountPath: "/data"}},
+				},
+			},
+			RestartPolicy:      apicorev1.RestartPolicyAlways,
+			EnableServiceLinks: new(bool),
+		},
+	}
+}
+
+func randomString(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("read random bytes failed: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniobackupschedule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crlisterv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/listers/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crconfig "github.com/3Xpl0it3r/minio-operator/pkg/config"
+	croperator "github.com/3Xpl0it3r/minio-operator/pkg/operator"
+)
+
+// reasons of events recorded on MinioBackupSchedule objects
+const (
+	EventReasonInvalidSchedule = "InvalidSchedule"
+	EventReasonBackupCreated   = "BackupCreated"
+	EventReasonBackupPruned    = "BackupPruned"
+)
+
+// maxMissedSchedules bounds the search of the latest missed schedule time
+const maxMissedSchedules = 1000
+
+type operator struct {
+	crClientSet    crclientset.Interface
+	scheduleLister crlisterv1alpha1.MinioBackupScheduleLister
+	backupLister   crlisterv1alpha1.MinioBackupLister
+	recorder       record.EventRecorder
+}
+
+func NewOperator(crClientSet crclientset.Interface, scheduleLister crlisterv1alpha1.MinioBackupScheduleLister, backupLister crlisterv1alpha1.MinioBackupLister, recorder record.EventRecorder) croperator.Operator {
+	return &operator{
+		crClientSet:    crClientSet,
+		scheduleLister: scheduleLister,
+		backupLister:   backupLister,
+		recorder:       recorder,
+	}
+}
+
+// Reconcile create a backup when the schedule is due and prune backups beyond retention. it is called on every
+// resync, so no timer is kept for the next schedule time
+func (o *operator) Reconcile(object interface{}) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(object.(string))
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get the namespace and name from key: %v : %v", object, err))
+		return nil
+	}
+	schedule, err := o.scheduleLister.MinioBackupSchedules(namespace).Get(name)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			// backups outlive their schedule, they are deleted explicitly
+			return nil
+		}
+		return fmt.Errorf("%s/%s get backup schedule failed %v", namespace, name, err)
+	}
+	scheduleCopy := schedule.DeepCopy()
+
+	cronSchedule, err := cron.ParseStandard(scheduleCopy.Spec.Schedule)
+	if err != nil {
+		o.recorder.Eventf(scheduleCopy, apicorev1.EventTypeWarning, EventReasonInvalidSchedule, "Parse schedule %q failed: %v", scheduleCopy.Spec.Schedule, err)
+		return nil
+	}
+	backups, err := o.backupLister.MinioBackups(namespace).List(labels.SelectorFromSet(labels.Set{crconfig.MinioBackupScheduleLabel: name}))
+	if err != nil {
+		return err
+	}
+
+	if !scheduleCopy.Spec.Suspend && !hasActiveBackup(backups) {
+		if scheduledTime, due := lastScheduleTime(cronSchedule, scheduleCopy, time.Now()); due {
+			backup, err := o.crClientSet.MiniooperatorV1alpha1().MinioBackups(namespace).Create(context.TODO(), newBackup(scheduleCopy, scheduledTime), metav1.CreateOptions{})
+			if err != nil && !k8serror.IsAlreadyExists(err) {
+				return fmt.Errorf("%s/%s create backup failed %v", namespace, name, err)
+			}
+			if err == nil {
+				o.recorder.Eventf(scheduleCopy, apicorev1.EventTypeNormal, EventReasonBackupCreated, "Created backup %s", backup.GetName())
+			}
+			scheduleCopy.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+			scheduleCopy.Status.LastBackup = getBackupName(scheduleCopy, scheduledTime)
+		}
+	}
+
+	if err = o.prune(scheduleCopy, backups); err != nil {
+		return fmt.Errorf("%s/%s prune backups failed %v", namespace, name, err)
+	}
+	if latest := latestBackup(backups, crapiv1alpha1.BackupPhaseCompleted); latest != nil {
+		scheduleCopy.Status.LastSuccessfulBackup = latest.GetName()
+	}
+
+	if reflect.DeepEqual(schedule.Status, scheduleCopy.Status) {
+		return nil
+	}
+	if _, err = o.crClientSet.MiniooperatorV1alpha1().MinioBackupSchedules(namespace).UpdateStatus(context.TODO(), scheduleCopy, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("%s/%s update status failed %v", namespace, name, err)
+	}
+	return nil
+}
+
+// prune delete the oldest completed and failed backups beyond retention, data in target is deleted by the
+// finalizer of backups
+func (o *operator) prune(schedule *crapiv1alpha1.MinioBackupSchedule, backups []*crapiv1alpha1.MinioBackup) error {
+	if schedule.Spec.Retention <= 0 {
+		return nil
+	}
+	for _, phase := range []crapiv1alpha1.BackupPhase{crapiv1alpha1.BackupPhaseCompleted, crapiv1alpha1.BackupPhaseFailed} {
+		finished := finishedBackups(backups, phase)
+		for index := int(schedule.Spec.Retention); index < len(finished); index++ {
+			backup := finished[index]
+			if err := o.crClientSet.MiniooperatorV1alpha1().MinioBackups(backup.GetNamespace()).Delete(context.TODO(), backup.GetName(), metav1.DeleteOptions{}); err != nil && !k8serror.IsNotFound(err) {
+				return err
+			}
+			o.recorder.Eventf(schedule, apicorev1.EventTypeNormal, EventReasonBackupPruned, "Deleted %s backup %s beyond retention %d", phase, backup.GetName(), schedule.Spec.Retention)
+		}
+	}
+	return nil
+}
+
+// lastScheduleTime return the latest schedule time which is not later than now, missed schedule times
+// before it are skipped. due is false if no backup should be created
+func lastScheduleTime(cronSchedule cron.Schedule, schedule *crapiv1alpha1.MinioBackupSchedule, now time.Time) (time.Time, bool) {
+	last := schedule.GetCreationTimestamp().Time
+	if schedule.Status.LastScheduleTime != nil {
+		last = schedule.Status.LastScheduleTime.Time
+	}
+	next := cronSchedule.Next(last)
+	if next.After(now) {
+		return time.Time{}, false
+	}
+	for i := 0; i < maxMissedSchedules; i++ {
+		following := cronSchedule.Next(next)
+		if following.After(now) {
+			break
+		}
+		next = following
+	}
+	return next, true
+}
+
+// newBackup return the backup of schedule at scheduledTime, it has the labels of schedule so that it is matched by
+// the --minio-selector of the operator instance which runs the schedule
+func newBackup(schedule *crapiv1alpha1.MinioBackupSchedule, scheduledTime time.Time) *crapiv1alpha1.MinioBackup {
+	backupLabels := make(map[string]string, len(schedule.GetLabels())+1)
+	for key, value := range schedule.GetLabels() {
+		backupLabels[key] = value
+	}
+	backupLabels[crconfig.MinioBackupScheduleLabel] = schedule.GetName()
+	return &crapiv1alpha1.MinioBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      getBackupName(schedule, scheduledTime),
+			Namespace: schedule.GetNamespace(),
+			Labels:    backupLabels,
+		},
+		Spec: *schedule.Spec.Template.DeepCopy(),
+	}
+}
+
+func getBackupName(schedule *crapiv1alpha1.MinioBackupSchedule, scheduledTime time.Time) string {
+	return fmt.Sprintf("%s-%d", schedule.GetName(), scheduledTime.Unix())
+}
+
+// hasActiveBackup return true if one of backups is not finished, schedules never run backups concurrently
+func hasActiveBackup(backups []*crapiv1alpha1.MinioBackup) bool {
+	for _, backup := range backups {
+		if backup.GetDeletionTimestamp() != nil {
+			continue
+		}
+		switch backup.Status.Phase {
+		case "", crapiv1alpha1.BackupPhasePending, crapiv1alpha1.BackupPhaseRunning:
+			return true
+		}
+	}
+	return false
+}
+
+// finishedBackups return backups in the given phase which are not being deleted, the latest one comes first
+func finishedBackups(backups []*crapiv1alpha1.MinioBackup, phase crapiv1alpha1.BackupPhase) []*crapiv1alpha1.MinioBackup {
+	var finished []*crapiv1alpha1.MinioBackup
+	for _, backup := range backups {
+		if backup.GetDeletionTimestamp() == nil && backup.Status.Phase == phase && backup.Status.CompletionTime != nil {
+			finished = append(finished, backup)
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[j].Status.CompletionTime.Before(finished[i].Status.CompletionTime)
+	})
+	return finished
+}
+
+func latestBackup(backups []*crapiv1alpha1.MinioBackup, phase crapiv1alpha1.BackupPhase) *crapiv1alpha1.MinioBackup {
+	if finished := finishedBackups(backups, phase); len(finished) > 0 {
+		return finished[0]
+	}
+	return nil
+}
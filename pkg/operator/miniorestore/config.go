@@ -0,0 +1,28 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniorestore
+
+import "time"
+
+// reasons of events recorded on MinioRestore objects
+const (
+	EventReasonRestoreStarted   = "RestoreStarted"
+	EventReasonRestoreCompleted = "RestoreCompleted"
+	EventReasonRestoreFailed    = "RestoreFailed"
+)
+
+// progressInterval is how often the status of a running restore is updated
+const progressInterval = 30 * time.Second
+
+// maxFailedKeys bounds the failed keys recorded in status
+const maxFailedKeys = 100
@@ -0,0 +1,334 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniorestore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	apicorev1 "k8s.io/api/core/v1"
+	k8serror "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	crclientset "github.com/3Xpl0it3r/minio-operator/pkg/client/clientset/versioned"
+	crlisterv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/client/listers/miniooperator.3xpl0it3r.cn/v1alpha1"
+	croperator "github.com/3Xpl0it3r/minio-operator/pkg/operator"
+	miniooperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/minio"
+	backupoperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/miniobackup"
+)
+
+type operator struct {
+	kubeClientSet kubernetes.Interface
+	crClientSet   crclientset.Interface
+	restoreLister crlisterv1alpha1.MinioRestoreLister
+	backupLister  crlisterv1alpha1.MinioBackupLister
+	minioLister   crlisterv1alpha1.MinioLister
+	recorder      record.EventRecorder
+
+	// running keeps the restores being copied by this process, keyed by namespace/name
+	lock    sync.Mutex
+	running map[string]*runningRestore
+}
+
+// runningRestore is a restore being copied in background, reconcile only starts and cancels it
+type runningRestore struct {
+	cancel context.CancelFunc
+}
+
+func NewOperator(kubeClientSet kubernetes.Interface, crClientSet crclientset.Interface, restoreLister crlisterv1alpha1.MinioRestoreLister, backupLister crlisterv1alpha1.MinioBackupLister, minioLister crlisterv1alpha1.MinioLister, recorder record.EventRecorder) croperator.Operator {
+	return &operator{
+		kubeClientSet: kubeClientSet,
+		crClientSet:   crClientSet,
+		restoreLister: restoreLister,
+		backupLister:  backupLister,
+		minioLister:   minioLister,
+		recorder:      recorder,
+		running:       map[string]*runningRestore{},
+	}
+}
+
+func (o *operator) Reconcile(object interface{}) error {
+	key := object.(string)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get the namespace and name from key: %v : %v", object, err))
+		return nil
+	}
+	restore, err := o.restoreLister.MinioRestores(namespace).Get(name)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			// the restore is removed from owners of the volume server by the garbage collector
+			o.cancel(key)
+			return nil
+		}
+		return fmt.Errorf("%s/%s get restore failed %v", namespace, name, err)
+	}
+	restoreCopy := restore.DeepCopy()
+	if restoreCopy.GetDeletionTimestamp() != nil {
+		o.cancel(key)
+		return nil
+	}
+
+	switch restoreCopy.Status.Phase {
+	case "", crapiv1alpha1.RestorePhasePending:
+		return o.start(key, restoreCopy)
+	case crapiv1alpha1.RestorePhaseRunning:
+		// the restore is running in this process, or the process which ran it has exited.
+		// a restarted restore begins from the first bucket again
+		if o.isRunning(key) {
+			return nil
+		}
+		// the lister may not have seen the result of a restore which is just finished
+		latest, err := o.crClientSet.MiniooperatorV1alpha1().MinioRestores(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("%s/%s get restore failed %v", namespace, name, err)
+		}
+		if latest.Status.Phase != crapiv1alpha1.RestorePhaseRunning {
+			return nil
+		}
+		return o.launch(key, latest)
+	}
+	return nil
+}
+
+// start validate the restore and mark it as running
+func (o *operator) start(key string, restore *crapiv1alpha1.MinioRestore) error {
+	switch restore.Spec.Overwrite {
+	case "", crapiv1alpha1.OverwriteNever, crapiv1alpha1.OverwriteAlways, crapiv1alpha1.OverwriteIfChanged:
+	default:
+		return o.fail(restore, fmt.Errorf("unknown overwrite policy %q", restore.Spec.Overwrite))
+	}
+	if _, _, err := o.location(restore); err != nil {
+		if k8serror.IsNotFound(err) {
+			return o.fail(restore, fmt.Errorf("backup %s is not found", restore.Spec.Backup))
+		}
+		return o.fail(restore, err)
+	}
+	if _, err := o.minioLister.Minios(restore.GetNamespace()).Get(restore.Spec.Minio); err != nil {
+		if k8serror.IsNotFound(err) {
+			return o.fail(restore, fmt.Errorf("minio %s is not found", restore.Spec.Minio))
+		}
+		return err
+	}
+	now := metav1.Now()
+	restore.Status = crapiv1alpha1.MinioRestoreStatus{Phase: crapiv1alpha1.RestorePhaseRunning, StartTime: &now}
+	restore, err := o.crClientSet.MiniooperatorV1alpha1().MinioRestores(restore.GetNamespace()).UpdateStatus(context.TODO(), restore, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("%s update status failed %v", key, err)
+	}
+	o.recorder.Eventf(restore, apicorev1.EventTypeNormal, EventReasonRestoreStarted, "Restore into minio %s is started", restore.Spec.Minio)
+	return o.launch(key, restore)
+}
+
+// launch connect to the destination minio and the backup, and restore buckets in background
+func (o *operator) launch(key string, restore *crapiv1alpha1.MinioRestore) error {
+	minio, err := o.minioLister.Minios(restore.GetNamespace()).Get(restore.Spec.Minio)
+	if err != nil {
+		if k8serror.IsNotFound(err) {
+			return o.fail(restore, fmt.Errorf("minio %s is not found", restore.Spec.Minio))
+		}
+		return err
+	}
+	target, backupName, err := o.location(restore)
+	if err != nil {
+		return o.fail(restore, err)
+	}
+	destination, err := miniooperator.NewApplicationClient(context.TODO(), o.kubeClientSet, minio)
+	if err != nil {
+		return fmt.Errorf("%s connect to minio %s failed %v", key, minio.GetName(), err)
+	}
+	backup, err := backupoperator.OpenTarget(context.TODO(), o.kubeClientSet, restore.GetNamespace(), target, backupName, o.volumeServer(restore, target))
+	if err != nil {
+		return fmt.Errorf("%s open backup failed %v", key, err)
+	}
+	overwrite := restore.Spec.Overwrite
+	if overwrite == "" {
+		overwrite = crapiv1alpha1.OverwriteNever
+	}
+	r := &restorer{backup: backup, destination: destination, overwrite: overwrite}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	running := &runningRestore{cancel: cancel}
+	o.lock.Lock()
+	o.running[key] = running
+	o.lock.Unlock()
+	go o.run(ctx, key, running, restore, target, r)
+	return nil
+}
+
+// run restore buckets and record the result into status, it returns without touching status if it is canceled
+func (o *operator) run(ctx context.Context, key string, running *runningRestore, restore *crapiv1alpha1.MinioRestore, target crapiv1alpha1.BackupTarget, r *restorer) {
+	defer func() {
+		// a canceled restore is deleted, its owner reference is removed by the garbage collector
+		if server := o.volumeServer(restore, target); server != nil && ctx.Err() == nil {
+			if err := server.Remove(context.TODO(), o.kubeClientSet); err != nil {
+				klog.Errorf("%s remove volume server failed: %v", key, err)
+			}
+		}
+		running.cancel()
+		o.lock.Lock()
+		if o.running[key] == running {
+			delete(o.running, key)
+		}
+		o.lock.Unlock()
+	}()
+	klog.Infof("%s begin to restore %s into minio %s", key, r.backup.Location, restore.Spec.Minio)
+
+	progressDone := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-progressDone:
+				return
+			case <-ticker.C:
+				if err := o.updateStatus(restore, r.progress.record); err != nil {
+					klog.Errorf("%s update progress failed: %v", key, err)
+				}
+			}
+		}
+	}()
+	buckets, created, runErr := r.run(ctx, restore.Spec.Buckets)
+	close(progressDone)
+	if ctx.Err() != nil {
+		// the restore is deleted
+		return
+	}
+
+	status := crapiv1alpha1.MinioRestoreStatus{}
+	r.progress.record(&status)
+	if runErr == nil && status.ObjectsFailed > 0 {
+		runErr = fmt.Errorf("%d objects failed to restore", status.ObjectsFailed)
+	}
+	if err := o.updateStatus(restore, func(status *crapiv1alpha1.MinioRestoreStatus) {
+		now := metav1.Now()
+		status.CompletionTime = &now
+		status.Buckets = buckets
+		status.CreatedBuckets = created
+		r.progress.record(status)
+		if runErr != nil {
+			status.Phase = crapiv1alpha1.RestorePhaseFailed
+			status.Message = runErr.Error()
+			return
+		}
+		status.Phase = crapiv1alpha1.RestorePhaseCompleted
+		status.Message = ""
+	}); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%s update status failed %v", key, err))
+		return
+	}
+	if runErr != nil {
+		o.recorder.Eventf(restore, apicorev1.EventTypeWarning, EventReasonRestoreFailed, "Restore failed: %v", runErr)
+	} else {
+		o.recorder.Eventf(restore, apicorev1.EventTypeNormal, EventReasonRestoreCompleted, "Restore completed, %d objects(%d bytes) restored, %d existing objects skipped, %d buckets created",
+			status.ObjectsRestored, status.BytesRestored, status.ObjectsSkipped, len(created))
+	}
+}
+
+// location return where the backup data is stored and the name of the backup which wrote it
+func (o *operator) location(restore *crapiv1alpha1.MinioRestore) (crapiv1alpha1.BackupTarget, string, error) {
+	if (restore.Spec.Backup == "") == (restore.Spec.Location == nil) {
+		return crapiv1alpha1.BackupTarget{}, "", errors.New("exactly one of backup and location must be set")
+	}
+	if location := restore.Spec.Location; location != nil {
+		if location.BackupName == "" {
+			return location.Target, "", errors.New("backupName is required by location")
+		}
+		return location.Target, location.BackupName, backupoperator.ValidateTarget(location.Target)
+	}
+	backup, err := o.backupLister.MinioBackups(restore.GetNamespace()).Get(restore.Spec.Backup)
+	if err != nil {
+		return crapiv1alpha1.BackupTarget{}, "", err
+	}
+	if backup.Status.Phase != crapiv1alpha1.BackupPhaseCompleted {
+		return backup.Spec.Target, "", fmt.Errorf("backup %s is not completed", backup.GetName())
+	}
+	return backup.Spec.Target, backup.GetName(), nil
+}
+
+// fail mark the restore as failed without retrying
+func (o *operator) fail(restore *crapiv1alpha1.MinioRestore, reason error) error {
+	o.recorder.Eventf(restore, apicorev1.EventTypeWarning, EventReasonRestoreFailed, "Restore failed: %v", reason)
+	// the volume server may be started by an earlier attempt
+	target, _, _ := o.location(restore)
+	if server := o.volumeServer(restore, target); server != nil {
+		if err := server.Remove(context.TODO(), o.kubeClientSet); err != nil {
+			return fmt.Errorf("remove volume server failed %v", err)
+		}
+	}
+	return o.updateStatus(restore, func(status *crapiv1alpha1.MinioRestoreStatus) {
+		now := metav1.Now()
+		status.Phase = crapiv1alpha1.RestorePhaseFailed
+		status.CompletionTime = &now
+		status.Message = reason.Error()
+	})
+}
+
+// updateStatus apply mutate on the latest status of restore
+func (o *operator) updateStatus(restore *crapiv1alpha1.MinioRestore, mutate func(status *crapiv1alpha1.MinioRestoreStatus)) error {
+	client := o.crClientSet.MiniooperatorV1alpha1().MinioRestores(restore.GetNamespace())
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := client.Get(context.TODO(), restore.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(&latest.Status)
+		_, err = client.UpdateStatus(context.TODO(), latest, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// volumeServer return the temporary minio pod shared by users of the persistent volume claim, nil for other targets
+func (o *operator) volumeServer(restore *crapiv1alpha1.MinioRestore, target crapiv1alpha1.BackupTarget) *backupoperator.VolumeServer {
+	claim := target.PersistentVolumeClaim
+	if claim == nil {
+		return nil
+	}
+	image := claim.Image
+	if image == "" {
+		minio := &crapiv1alpha1.Minio{}
+		if destination, err := o.minioLister.Minios(restore.GetNamespace()).Get(restore.Spec.Minio); err == nil {
+			minio = destination.DeepCopy()
+		}
+		crapiv1alpha1.MinioDefaulter(minio)
+		image = minio.Spec.Image
+	}
+	return backupoperator.NewVolumeServer(restore.GetNamespace(), claim.ClaimName, image, *metav1.NewControllerRef(restore, crapiv1alpha1.SchemeGroupVersion.WithKind("MinioRestore")))
+}
+
+func (o *operator) isRunning(key string) bool {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	_, ok := o.running[key]
+	return ok
+}
+
+func (o *operator) cancel(key string) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if running, ok := o.running[key]; ok {
+		running.cancel()
+		delete(o.running, key)
+	}
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2022 The minio-operator Authors.
+Licensed under the Apache License, PROJECT_VERSION 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package miniorestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/minio/minio-go/v7"
+
+	crapiv1alpha1 "github.com/3Xpl0it3r/minio-operator/pkg/apis/miniooperator.3xpl0it3r.cn/v1alpha1"
+	backupoperator "github.com/3Xpl0it3r/minio-operator/pkg/operator/miniobackup"
+)
+
+// progress is the statistics of a running restore, it is updated by the restorer and read by the status reporter
+type progress struct {
+	objectsRestored int64
+	objectsSkipped  int64
+	bytesRestored   int64
+	objectsFailed   int64
+
+	lock       sync.Mutex
+	failedKeys []string
+}
+
+func (p *progress) fail(key string) {
+	atomic.AddInt64(&p.objectsFailed, 1)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.failedKeys) < maxFailedKeys {
+		p.failedKeys = append(p.failedKeys, key)
+	}
+}
+
+// record copy the statistics into status
+func (p *progress) record(status *crapiv1alpha1.MinioRestoreStatus) {
+	status.ObjectsRestored = atomic.LoadInt64(&p.objectsRestored)
+	status.ObjectsSkipped = atomic.LoadInt64(&p.objectsSkipped)
+	status.BytesRestored = atomic.LoadInt64(&p.bytesRestored)
+	status.ObjectsFailed = atomic.LoadInt64(&p.objectsFailed)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	status.FailedKeys = append([]string(nil), p.failedKeys...)
+}
+
+// restorer copy buckets of a backup into the destination minio. a failed object is recorded and skipped,
+// only errors which stop the whole restore are returned
+type restorer struct {
+	backup      *backupoperator.Target
+	destination *minio.Client
+	overwrite   crapiv1alpha1.OverwritePolicy
+	progress    progress
+}
+
+// run restore the given buckets, all buckets of the backup are restored if buckets is empty.
+// it returns the restored buckets and the buckets which are created
+func (r *restorer) run(ctx context.Context, buckets []string) (restored []string, created []string, err error) {
+	manifest, err := r.backup.ReadManifest(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(buckets) == 0 {
+		buckets = manifest.Buckets
+	}
+	for _, bucket := range buckets {
+		if !contains(manifest.Buckets, bucket) {
+			return nil, nil, fmt.Errorf("bucket %s is not in the backup", bucket)
+		}
+	}
+	for _, bucket := range buckets {
+		exists, err := r.destination.BucketExists(ctx, bucket)
+		if err != nil {
+			return restored, created, fmt.Errorf("check bucket %s failed: %v", bucket, err)
+		}
+		if !exists {
+			settings, err := r.backup.ReadBucketSettings(ctx, bucket)
+			if err != nil {
+				return restored, created, err
+			}
+			if err = backupoperator.CreateBucket(ctx, r.destination, settings); err != nil {
+				return restored, created, fmt.Errorf("create bucket %s failed: %v", bucket, err)
+			}
+			created = append(created, bucket)
+		}
+		if err = r.restoreBucket(ctx, bucket); err != nil {
+			return restored, created, fmt.Errorf("restore bucket %s failed: %v", bucket, err)
+		}
+		restored = append(restored, bucket)
+	}
+	return restored, created, nil
+}
+
+func (r *restorer) restoreBucket(ctx context.Context, bucket string) error {
+	prefix := r.backup.Layout.BucketPrefix(bucket)
+	for info := range r.backup.Client.ListObjects(ctx, r.backup.Layout.Bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if info.Err != nil {
+			return info.Err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := strings.TrimPrefix(info.Key, prefix)
+		skipped, err := r.restoreObject(ctx, bucket, key, info.Key)
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			r.progress.fail(bucket + "/" + key)
+		case skipped:
+			atomic.AddInt64(&r.progress.objectsSkipped, 1)
+		default:
+			atomic.AddInt64(&r.progress.objectsRestored, 1)
+			atomic.AddInt64(&r.progress.bytesRestored, info.Size)
+		}
+	}
+	return nil
+}
+
+// restoreObject copy the backed up object into the destination, skipped is true if the existing object is kept
+func (r *restorer) restoreObject(ctx context.Context, bucket, key, backupKey string) (bool, error) {
+	var existing *minio.ObjectInfo
+	if r.overwrite != crapiv1alpha1.OverwriteAlways {
+		info, err := r.destination.StatObject(ctx, bucket, key, minio.StatObjectOptions{})
+		switch {
+		case err == nil:
+			if r.overwrite != crapiv1alpha1.OverwriteIfChanged {
+				return true, nil
+			}
+			existing = &info
+		case !backupoperator.IsNotFound(err):
+			return false, err
+		}
+	}
+	object, err := r.backup.Client.GetObject(ctx, r.backup.Layout.Bucket, backupKey, minio.GetObjectOptions{})
+	if err != nil {
+		return false, err
+	}
+	defer object.Close()
+	stat, err := object.Stat()
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && existing.ETag == backupoperator.SourceETag(stat) {
+		return true, nil
+	}
+	_, err = r.destination.PutObject(ctx, bucket, key, object, stat.Size, minio.PutObjectOptions{
+		ContentType:  stat.ContentType,
+		UserMetadata: backupoperator.SourceMetadata(stat),
+	})
+	return false, err
+}
+
+func contains(items []string, item string) bool {
+	for _, i := range items {
+		if i == item {
+			return true
+		}
+	}
+	return false
+}